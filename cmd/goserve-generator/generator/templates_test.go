@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/cmd/goserve-generator/file"
+	testutils "github.com/softwareplace/goserve/internal/utils"
+)
+
+const templatesSpec = `
+openapi: 3.0.3
+info:
+  title: pets
+  version: 1.0.0
+paths:
+  /owners/{ownerId}/pets:
+    get:
+      operationId: listPets
+      summary: Lists the pets of an owner.
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+      responses:
+        '200':
+          description: ok
+    post:
+      operationId: addPet
+      summary: Adds a pet to an owner.
+      parameters:
+        - name: ownerId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: created
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+// generateTemplates runs oapi-codegen with the goserve templates on the spec, as the generated
+// projects do, and returns the parsed generated file.
+func generateTemplates(t *testing.T, spec string) *ast.File {
+	codegen, err := exec.LookPath("oapi-codegen")
+	if err != nil {
+		t.Skip("oapi-codegen is not installed")
+	}
+
+	templates := file.JoinPath(testutils.ProjectBasePath(), "resource/templates")
+	config := "package: gen\n" +
+		"generate:\n  gorilla-server: true\n  models: true\n" +
+		"output: ./api.gen.go\n" +
+		"output-options:\n  user-templates:\n"
+	for _, name := range []string{
+		"imports.tmpl",
+		"param-types.tmpl",
+		"request-bodies.tmpl",
+		"typedef.tmpl",
+		"gorilla/gorilla-register.tmpl",
+		"gorilla/gorilla-middleware.tmpl",
+		"gorilla/gorilla-interface.tmpl",
+	} {
+		config += fmt.Sprintf("    %s: %s\n", name, file.JoinPath(templates, name))
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(file.JoinPath(dir, "config.yaml"), []byte(config), 0o600))
+	require.NoError(t, os.WriteFile(file.JoinPath(dir, "spec.yaml"), []byte(spec), 0o600))
+
+	cmd := exec.Command(codegen, "--config", "config.yaml", "spec.yaml")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	generated, err := parser.ParseFile(token.NewFileSet(), file.JoinPath(dir, "api.gen.go"), nil, parser.ParseComments)
+	require.NoError(t, err)
+	return generated
+}
+
+// methodOf returns the declaration of the method with the given name.
+func methodOf(t *testing.T, generated *ast.File, name string) *ast.FuncDecl {
+	for _, decl := range generated.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	require.Failf(t, "method not generated", "%s", name)
+	return nil
+}
+
+// answersBindFailure reports whether the handler answers the error of BindRequestParams with
+// ctx.Problem(err) in an `if err != nil` branch.
+func answersBindFailure(handler *ast.FuncDecl) bool {
+	binds, answers := false, false
+	ast.Inspect(handler.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if selector, ok := node.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "BindRequestParams" {
+				binds = true
+			}
+		case *ast.IfStmt:
+			if condition, ok := node.Cond.(*ast.BinaryExpr); ok && isIdent(condition.X, "err") &&
+				condition.Op == token.NEQ && isIdent(condition.Y, "nil") {
+				answers = answers || callsProblem(node.Body)
+			}
+		}
+		return true
+	})
+	return binds && answers
+}
+
+func callsProblem(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if ok && isIdent(selector.X, "ctx") && selector.Sel.Name == "Problem" &&
+			len(call.Args) == 1 && isIdent(call.Args[0], "err") {
+			found = true
+		}
+		return true
+	})
+	return found
+}
+
+// tagsOf returns the struct tags of the fields of the generated struct type with the given name.
+func tagsOf(t *testing.T, generated *ast.File, name string) map[string]reflect.StructTag {
+	tags := map[string]reflect.StructTag{}
+	ast.Inspect(generated, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != name {
+			return true
+		}
+
+		for _, field := range spec.Type.(*ast.StructType).Fields.List {
+			if field.Tag == nil || len(field.Names) == 0 {
+				continue
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			require.NoError(t, err)
+			tags[field.Names[0].Name] = reflect.StructTag(tag)
+		}
+		return false
+	})
+	require.NotEmpty(t, tags, "struct %s not generated", name)
+	return tags
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func TestTemplates_GeneratedCode(t *testing.T) {
+	generated := generateTemplates(t, templatesSpec)
+
+	t.Run("should answer bind failures with the structured problem of the request error", func(t *testing.T) {
+		for _, name := range []string{"ListPets", "AddPet"} {
+			require.True(t, answersBindFailure(methodOf(t, generated, name)), name)
+		}
+	})
+
+	t.Run("should tag the parameters with their source", func(t *testing.T) {
+		tags := tagsOf(t, generated, "ListPetsClientRequest")
+
+		require.Equal(t, "ownerId", tags["OwnerId"].Get("path"))
+		require.Equal(t, "limit", tags["Limit"].Get("query"))
+		require.Equal(t, "X-Request-Id", tags["XRequestId"].Get("header"))
+		require.Equal(t, "X-Request-Id", tagsOf(t, generated, "AddPetClientRequest")["XRequestId"].Get("header"))
+	})
+}
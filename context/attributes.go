@@ -0,0 +1,153 @@
+package context
+
+import (
+	"sync"
+)
+
+// Key identifies a typed attribute stored on a Request. Keys are compared by identity, as
+// context.WithValue does, so two keys created with the same name never collide: declare them
+// once, usually as package level variables, and share them between the middleware that sets
+// the value and the handlers that read it.
+//
+// Example:
+//
+//	var TenantKey = goservectx.NewKey[*Tenant]("tenant")
+type Key[V any] struct {
+	id *keyId
+}
+
+// keyId is the identity of a Key, its name is only used to describe the attribute.
+type keyId struct {
+	name string
+}
+
+// NewKey creates a new typed attribute key with the given name. The name describes the key,
+// each call returns a distinct key.
+func NewKey[V any](name string) Key[V] {
+	return Key[V]{id: &keyId{name: name}}
+}
+
+// Name returns the name of the key.
+func (k Key[V]) Name() string {
+	if k.id == nil {
+		return ""
+	}
+	return k.id.name
+}
+
+// Attributes is a key/value store attached to a Request. It is safe for concurrent use,
+// so handlers can read and write attributes from goroutines started while processing the request.
+type Attributes struct {
+	mu     sync.RWMutex
+	values map[*keyId]any
+}
+
+// AttributeHolder is implemented by types that carry Attributes, such as Request and SampleContext.
+type AttributeHolder interface {
+	Attributes() *Attributes
+}
+
+// Len returns the number of attributes stored.
+func (a *Attributes) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.values)
+}
+
+// Names returns the names of the attributes stored.
+func (a *Attributes) Names() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	names := make([]string, 0, len(a.values))
+	for id := range a.values {
+		names = append(names, id.name)
+	}
+	return names
+}
+
+func (a *Attributes) set(id *keyId, value any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.values == nil {
+		a.values = make(map[*keyId]any)
+	}
+	a.values[id] = value
+}
+
+func (a *Attributes) get(id *keyId) (any, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	value, ok := a.values[id]
+	return value, ok
+}
+
+func (a *Attributes) delete(id *keyId) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.values, id)
+}
+
+func (a *Attributes) snapshot() *Attributes {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	values := make(map[*keyId]any, len(a.values))
+	for id, value := range a.values {
+		values[id] = value
+	}
+	return &Attributes{values: values}
+}
+
+// Set stores a typed attribute, replacing any value previously stored with the same key.
+//
+// Parameters:
+//   - holder: The Request, or any other AttributeHolder, to store the value on.
+//   - key: The typed key of the attribute.
+//   - value: The value to store.
+//
+// Example usage:
+//
+//	goservectx.Set(ctx, TenantKey, tenant)
+func Set[V any](holder AttributeHolder, key Key[V], value V) {
+	holder.Attributes().set(key.id, value)
+}
+
+// Get retrieves a typed attribute.
+//
+// Parameters:
+//   - holder: The Request, SampleContext, or any other AttributeHolder to read the value from.
+//   - key: The typed key of the attribute.
+//
+// Returns:
+//   - V: The stored value, or the zero value of V if not found.
+//   - bool: True if a value of type V was stored with the given key.
+//
+// Example usage:
+//
+//	tenant, ok := goservectx.Get(ctx, TenantKey)
+func Get[V any](holder AttributeHolder, key Key[V]) (V, bool) {
+	value, ok := holder.Attributes().get(key.id)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	typed, ok := value.(V)
+	return typed, ok
+}
+
+// GetOrElse retrieves a typed attribute, returning defaultValue if it is not found.
+func GetOrElse[V any](holder AttributeHolder, key Key[V], defaultValue V) V {
+	if value, ok := Get(holder, key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// Delete removes a typed attribute.
+func Delete[V any](holder AttributeHolder, key Key[V]) {
+	holder.Attributes().delete(key.id)
+}
@@ -0,0 +1,92 @@
+package context
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tenant struct {
+	Name string
+}
+
+var (
+	tenantKey   = NewKey[*tenant]("tenant")
+	featuresKey = NewKey[[]string]("features")
+)
+
+func TestAttributes_SetAndGet(t *testing.T) {
+	ctx := newMockContext()
+
+	_, ok := Get(ctx, tenantKey)
+	require.False(t, ok)
+
+	Set(ctx, tenantKey, &tenant{Name: "acme"})
+	Set(ctx, featuresKey, []string{"beta"})
+
+	value, ok := Get(ctx, tenantKey)
+	require.True(t, ok)
+	require.Equal(t, "acme", value.Name)
+	require.Equal(t, []string{"beta"}, GetOrElse(ctx, featuresKey, nil))
+
+	Delete(ctx, featuresKey)
+	require.Equal(t, []string{"default"}, GetOrElse(ctx, featuresKey, []string{"default"}))
+}
+
+func TestAttributes_KeysWithTheSameName(t *testing.T) {
+	ctx := newMockContext()
+	other := NewKey[*tenant]("tenant")
+	Set(ctx, tenantKey, &tenant{Name: "acme"})
+
+	_, ok := Get(ctx, other)
+	require.False(t, ok)
+
+	Set(ctx, other, &tenant{Name: "other"})
+	value, _ := Get(ctx, tenantKey)
+	require.Equal(t, "acme", value.Name)
+	require.Equal(t, []string{"tenant", "tenant"}, ctx.Attributes().Names())
+}
+
+func TestAttributes_SharedThroughMiddlewareChain(t *testing.T) {
+	ctx := newMockContext()
+	Set(ctx, tenantKey, &tenant{Name: "acme"})
+
+	same := Of[*mockPrincipal](*ctx.Writer, ctx.Request, "next")
+	value, ok := Get(same, tenantKey)
+	require.True(t, ok)
+	require.Equal(t, "acme", value.Name)
+}
+
+func TestAttributes_ConcurrentAccess(t *testing.T) {
+	ctx := newMockContext()
+	counterKey := NewKey[int]("counter")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			Set(ctx, counterKey, i)
+			_, _ = Get(ctx, counterKey)
+		}(i)
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, ctx.Attributes().Len())
+}
+
+func TestAttributes_SampleSnapshotAndFlush(t *testing.T) {
+	ctx := newMockContext()
+	Set(ctx, tenantKey, &tenant{Name: "acme"})
+
+	sample := ctx.GetSample()
+	ctx.Flush()
+
+	_, ok := Get(ctx, tenantKey)
+	require.False(t, ok)
+
+	value, ok := Get(sample, tenantKey)
+	require.True(t, ok)
+	require.Equal(t, "acme", value.Name)
+}
@@ -2,14 +2,17 @@ package context
 
 import (
 	"context"
+	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/softwareplace/goserve/env"
+	"github.com/softwareplace/goserve/fieldset"
 	"github.com/softwareplace/goserve/security/router"
+	"github.com/softwareplace/goserve/view"
 )
 
 const (
@@ -40,6 +43,15 @@ type SampleContext[T Principal] struct {
 	PathValues          map[string]string      // A map of route variables extracted from the request URL. Useful for handling dynamic URL parameters in the API endpoints.
 	Headers             map[string][]string    // Headers contains a mapping of header keys to their respective values from the incoming HTTP request.
 	QueryValues         map[string][]string    // A map containing the query parameters from the request URL. Each key corresponds to a query parameter name, and the value is a slice of strings representing the values of that parameter. Useful for processing and validating query parameters in API endpoints.
+	attributes          *Attributes            // A snapshot of the request attributes taken when the sample was created.
+}
+
+// Attributes returns the snapshot of the request attributes taken when the sample was created.
+func (s SampleContext[T]) Attributes() *Attributes {
+	if s.attributes == nil {
+		return &Attributes{}
+	}
+	return s.attributes
 }
 
 func (ctx *Request[T]) GetSample() SampleContext[T] {
@@ -52,7 +64,8 @@ func (ctx *Request[T]) GetSample() SampleContext[T] {
 		AuthorizationClaims: ctx.AuthorizationClaims,
 		ApiKeyClaims:        ctx.ApiKeyClaims,
 		AccessId:            ctx.AccessId,
-		PathValues:          ctx.PathValues,
+		PathValues:          ctx.Vars(),
+		attributes:          ctx.Attributes().snapshot(),
 	}
 }
 
@@ -67,12 +80,33 @@ type Request[T Principal] struct {
 	AuthorizationClaims map[string]interface{} // A set of claims derived from the authorization token, providing additional metadata about the requester (e.g., roles, permissions, expiration).
 	ApiKeyClaims        map[string]interface{} // A set of claims derived from the API key, detailing metadata associated with the key (e.g., usage limits, allowed resources).
 	AccessId            string                 // A unique identifier representing access to a specific resource or API, often used for auditing or tracking access patterns.
-	PathValues          map[string]string      // A map of route variables extracted from the request URL. Useful for handling dynamic URL parameters in the API endpoints.
+	PathValues          map[string]string      // A map of route variables extracted from the request URL. Filled on the first call to Vars, or by the helpers reading it, such as PathValueOf.
 	Headers             map[string][]string    // Headers contains a mapping of header keys to their respective values from the incoming HTTP request.
-	QueryValues         map[string][]string    // A map containing the query parameters from the request URL. Each key corresponds to a query parameter name, and the value is a slice of strings representing the values of that parameter. Useful for processing and validating query parameters in API endpoints.
+	QueryValues         map[string][]string    // A map containing the query parameters from the request URL. Each key corresponds to a query parameter name, and the value is a slice of strings representing the values of that parameter. Filled on the first call to Query, or by the helpers reading it, such as QueryOf.
 	Completed           bool                   // Completed indicates whether the task or process has been finished successfully or not.
 	ResourceRoles       []string               // ResourceRoles contains a list of roles that are required for the request to be processed.
 	IsRequiredRoles     bool                   // IsRequiredRoles indicates whether the request requires roles to be processed.
+	attributes          *Attributes            // attributes holds the typed values shared between middlewares and handlers. See Set and Get.
+	etagMode            ETagMode               // etagMode selects the ETag of the JSON responses. See UseETag.
+	errorFormat         ErrorFormat            // errorFormat selects the body of the error responses. See UseErrorFormat.
+	locale              string                 // locale is the locale selected from the Accept-Language header. See Locale.
+	fieldSelection      *fieldset.Selection    // fieldSelection filters the members of the successful JSON responses. See UseFieldSelection.
+	envelope            *Envelope              // envelope wraps the JSON responses. See UseEnvelope.
+	views               *view.Engine           // views renders the HTML responses. See UseViews.
+	tx                  *sql.Tx                // tx is the database/sql transaction of the request. See Tx.
+	handle              *handle[T]             // handle links the *http.Request to this Request until it is released.
+	pooled              bool                   // pooled reports whether Release returns this Request to the pool.
+	writer              http.ResponseWriter    // writer is the ResponseWriter Writer points to.
+	responseWriter      responseWriter         // responseWriter wraps the http.ResponseWriter of the request, pooled with the Request.
+}
+
+// Attributes returns the attribute store of the request, used through Set and Get to share
+// typed values, such as a tenant or a loaded entity, between middlewares and handlers.
+func (ctx *Request[T]) Attributes() *Attributes {
+	if ctx.attributes == nil {
+		ctx.attributes = &Attributes{}
+	}
+	return ctx.attributes
 }
 
 // Of retrieves the Request object from the request's context if it already exists.
@@ -83,6 +117,10 @@ type Request[T Principal] struct {
 // such as API key, authorization token, and a unique session ID. The new context or the retrieved existing
 // context is linked to the request to facilitate data sharing throughout the request's lifecycle.
 //
+// New contexts are taken from a pool and are owned by the caller that created them, which must
+// hand them back with Release once the response is written; on a goserve server, the root
+// middleware does it. The query parameters and route variables are only parsed when first read.
+//
 // Type Parameters:
 //   - T: A type that implements the Principal interface, which facilitates the storage
 //     and management of additional API-related data for the request.
@@ -100,10 +138,8 @@ type Request[T Principal] struct {
 //	ctx := Of[MyContextData](w, r, "MyReference")
 //	ctx.GetSessionId() // Access session id
 func Of[T Principal](w http.ResponseWriter, r *http.Request, reference string) *Request[T] {
-	currentContext := r.Context().Value(apiAccessContextKey)
-
-	if currentContext != nil {
-		ctx := currentContext.(*Request[T])
+	if current, ok := r.Context().Value(apiAccessContextKey).(*handle[T]); ok && current.ctx != nil {
+		ctx := current.ctx
 		ctx.updateContext(r)
 		return ctx
 	}
@@ -116,8 +152,10 @@ func Of[T Principal](w http.ResponseWriter, r *http.Request, reference string) *
 // reuse of sensitive data or to prepare for cleanup at the end of a request.
 //
 // This function clears sensitive information such as API key, authorization
-// tokens, claims, and other metadata. It also nils out the Writer and Request
-// pointers to avoid accidental usage after the flush.
+// tokens, claims, request attributes and other metadata. It also nils out the
+// Writer and Request pointers to avoid accidental usage after the flush, and
+// unlinks the Request from the *http.Request, so Of no longer returns it.
+// Use Release instead to also return the Request to the pool.
 //
 // Usage Example:
 //
@@ -125,41 +163,25 @@ func Of[T Principal](w http.ResponseWriter, r *http.Request, reference string) *
 //	// Process request here...
 //	ctx.Flush() // Reset the context to its default state for cleanup.
 func (ctx *Request[T]) Flush() {
-	apiRequestContext := context.WithValue(ctx.Request.Context(), apiAccessContextKey, nil)
-	ctx.Request = ctx.Request.WithContext(apiRequestContext)
-
-	ctx.Writer = nil
-	ctx.Request = nil
-	ctx.AuthorizationClaims = nil
-	ctx.ApiKeyClaims = nil
-	ctx.Authorization = ""
-	ctx.ApiKeyId = ""
-	ctx.ApiKey = ""
-	ctx.sessionId = ""
-	ctx.AccessId = ""
-	ctx.Principal = nil
+	ctx.reset()
 }
 
 func createNewContext[T Principal](
 	w http.ResponseWriter,
 	r *http.Request, reference string,
 ) *Request[T] {
-	w.Header().Set("Content-Type", "application/json")
-
-	resourceRoles, isRequiredRoles := router.GetRolesForPath(r.Method, r.URL.Path)
-
-	ctx := Request[T]{
-		Writer:          &w,
-		Request:         r,
-		PathValues:      mux.Vars(r),
-		QueryValues:     r.URL.Query(),
-		Headers:         r.Header,
-		sessionId:       uuid.New().String(),
-		ApiKey:          r.Header.Get(XApiKey),
-		Authorization:   r.Header.Get(Authorization),
-		ResourceRoles:   resourceRoles,
-		IsRequiredRoles: isRequiredRoles,
-	}
+	ctx := acquire[T]()
+	ctx.wrapWriter(w)
+	(*ctx.Writer).Header().Set("Content-Type", "application/json")
+
+	ctx.ResourceRoles, ctx.IsRequiredRoles = router.GetRolesForPath(r.Method, r.URL.Path)
+	// Each request gets its own attribute store, created before any goroutine can share it.
+	ctx.attributes = &Attributes{}
+	ctx.Headers = r.Header
+	ctx.sessionId = uuid.New().String()
+	ctx.ApiKey = r.Header.Get(XApiKey)
+	ctx.Authorization = r.Header.Get(Authorization)
+	ctx.handle = &handle[T]{ctx: ctx}
 
 	isHelthCheckPath := r.URL.Path == env.HealthResourcePath
 
@@ -167,12 +189,83 @@ func createNewContext[T Principal](
 		log.Printf("%s -> initialized a context with session id: %s", reference, ctx.sessionId)
 	}
 	ctx.updateContext(r)
-	return &ctx
+	return ctx
 }
 
 func (ctx *Request[T]) updateContext(r *http.Request) {
-	apiRequestContext := context.WithValue(ctx.Request.Context(), apiAccessContextKey, ctx)
-	ctx.Request = r.WithContext(apiRequestContext)
+	if r.Context().Value(apiAccessContextKey) == ctx.handle {
+		// The request already carries the handle, such as when the router passes it on unchanged.
+		ctx.Request = r
+		return
+	}
+	ctx.Request = r.WithContext(context.WithValue(r.Context(), apiAccessContextKey, ctx.handle))
+}
+
+// Context returns the context.Context of the current request. It is canceled when the client
+// disconnects or when the request deadline configured on the server is exceeded.
+//
+// Always use it instead of keeping a reference to ctx.Request.Context(), since the underlying
+// request is replaced as it goes through the middleware chain. Pass it to outbound calls,
+// such as http.Config.WithContext or database/sql methods, to propagate the deadline.
+//
+// Example usage:
+//
+//	rows, err := db.QueryContext(ctx.Context(), query)
+func (ctx *Request[T]) Context() context.Context {
+	if ctx.Request == nil {
+		return context.Background()
+	}
+	return ctx.Request.Context()
+}
+
+// IsCanceled reports whether the request context was canceled, either because the client
+// disconnected or because the request deadline was exceeded.
+func (ctx *Request[T]) IsCanceled() bool {
+	return ctx.Context().Err() != nil
+}
+
+// ResponseWriter returns the ResponseWriter of the request, which records the status code, size and
+// time to first byte of the response. It returns nil if the Writer was not wrapped, as after Flush.
+func (ctx *Request[T]) ResponseWriter() ResponseWriter {
+	if ctx.Writer == nil {
+		return nil
+	}
+	writer, _ := (*ctx.Writer).(ResponseWriter)
+	return writer
+}
+
+// ResponseStatus returns the status code sent to the client, or zero if the headers were not sent yet.
+func (ctx *Request[T]) ResponseStatus() int {
+	if writer := ctx.ResponseWriter(); writer != nil {
+		return writer.Status()
+	}
+	return 0
+}
+
+// ResponseSize returns the number of body bytes written to the client.
+func (ctx *Request[T]) ResponseSize() int64 {
+	if writer := ctx.ResponseWriter(); writer != nil {
+		return writer.Size()
+	}
+	return 0
+}
+
+// HeadersSent reports whether the response headers were already sent to the client, in which case
+// the status code and headers can no longer be changed.
+func (ctx *Request[T]) HeadersSent() bool {
+	if writer := ctx.ResponseWriter(); writer != nil {
+		return writer.Written()
+	}
+	return false
+}
+
+// TimeToFirstByte returns the time elapsed between the creation of the context and the moment the
+// response headers were sent, or zero if they were not sent yet.
+func (ctx *Request[T]) TimeToFirstByte() time.Duration {
+	if writer := ctx.ResponseWriter(); writer != nil {
+		return writer.TimeToFirstByte()
+	}
+	return 0
 }
 
 // Next forwards the request to the next HTTP handler in the middleware chain.
@@ -187,8 +280,30 @@ func (ctx *Request[T]) Done() {
 
 func (ctx *Request[T]) Write(body any, status int) {
 	if !ctx.Completed {
-		(*ctx.Writer).WriteHeader(status)
-		err := encoder(*ctx.Writer, body)
+		var response Enveloped
+		data := body
+		if ctx.envelope != nil {
+			response = envelopeParts(body, status)
+			data = response.Data
+		}
+
+		data, err := ctx.selectFields(data, status)
+		if err != nil {
+			log.Printf("Error selecting response fields: %v", err)
+		}
+
+		payload := data
+		if ctx.envelope != nil {
+			response.Data = data
+			payload = ctx.envelop(response, body, status)
+		}
+
+		if status == http.StatusOK && (ctx.etagMode != ETagDisabled || (*ctx.Writer).Header().Get("ETag") != "") {
+			err = ctx.writeWithETag(data, payload, status)
+		} else {
+			(*ctx.Writer).WriteHeader(status)
+			err = encoder(*ctx.Writer, payload)
+		}
 		if err != nil {
 			log.Printf("Error encoding response: %v", err)
 		}
@@ -2,6 +2,7 @@ package context
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -92,7 +93,7 @@ func TestRequest_Write(t *testing.T) {
 	ctx.Write(body, http.StatusOK)
 	result := httptest.NewRecorder()
 	json.NewEncoder(result).Encode(body)
-	assert.JSONEq(t, result.Body.String(), (*ctx.Writer).(*httptest.ResponseRecorder).Body.String())
+	assert.JSONEq(t, result.Body.String(), ctx.ResponseWriter().Unwrap().(*httptest.ResponseRecorder).Body.String())
 }
 
 func TestRequest_ErrorResponses(t *testing.T) {
@@ -153,7 +154,7 @@ func TestRequest_ErrorResponses(t *testing.T) {
 	for _, err := range expectedError {
 		ctx := newMockContext()
 		err.callback(ctx)
-		bodyString := (*ctx.Writer).(*httptest.ResponseRecorder).Body.String()
+		bodyString := ctx.ResponseWriter().Unwrap().(*httptest.ResponseRecorder).Body.String()
 
 		if strings.Contains(bodyString, err.error) {
 			t.Logf("Response body is good %s", bodyString)
@@ -169,7 +170,7 @@ func TestRequest_WriteReader(t *testing.T) {
 	reader := bytes.NewReader(content)
 	err := ctx.WriteReader(reader, "test.txt")
 	assert.NoError(t, err)
-	assert.Contains(t, (*ctx.Writer).(*httptest.ResponseRecorder).Header().Get("Content-Disposition"), "attachment")
+	assert.Contains(t, ctx.ResponseWriter().Unwrap().(*httptest.ResponseRecorder).Header().Get("Content-Disposition"), "attachment")
 }
 
 func TestRequest_CreateNew(t *testing.T) {
@@ -217,3 +218,16 @@ func TestWriteResponse(t *testing.T) {
 	require.Equal(t, http.StatusOK, recorder.Code)
 	require.Equal(t, recorder.Body.String(), "")
 }
+
+func TestRequest_ContextFollowsUpdatedRequest(t *testing.T) {
+	ctx := newMockContext()
+	require.False(t, ctx.IsCanceled())
+
+	canceled, cancel := context.WithCancel(ctx.Request.Context())
+	cancel()
+
+	same := Of[*mockPrincipal](*ctx.Writer, ctx.Request.WithContext(canceled), "next")
+	require.Same(t, ctx, same)
+	require.True(t, ctx.IsCanceled())
+	require.ErrorIs(t, ctx.Context().Err(), context.Canceled)
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/softwareplace/goserve/security/cookie"
+)
+
+// Cookie returns the raw value of the request cookie with the given name.
+//
+// Returns:
+//   - string: The cookie value, or an empty string if not found.
+//   - bool: True if the request carries the cookie.
+func (ctx *Request[T]) Cookie(name string) (string, bool) {
+	c, err := ctx.Request.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return c.Value, true
+}
+
+// SetCookie adds a Set-Cookie header to the response. It must be called before the response is written.
+func (ctx *Request[T]) SetCookie(c *http.Cookie) {
+	http.SetCookie(*ctx.Writer, c)
+}
+
+// DeleteCookie expires the cookie with the given name and path on the client.
+func (ctx *Request[T]) DeleteCookie(name string, path string) {
+	ctx.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     path,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// SecureCookie reads the request cookie with the given name and verifies it with the codec.
+//
+// Parameters:
+//   - codec: The cookie.Codec used when the cookie was set, either signed or encrypted.
+//   - name: The cookie name.
+//
+// Returns:
+//   - string: The plain cookie value.
+//   - error: http.ErrNoCookie if the cookie is missing, or the codec error if the value is invalid or expired.
+//
+// Example usage:
+//
+//	codec := cookie.NewEncrypted([]byte(secret), 24*time.Hour)
+//	theme, err := ctx.SecureCookie(codec, "theme")
+func (ctx *Request[T]) SecureCookie(codec cookie.Codec, name string) (string, error) {
+	c, err := ctx.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return codec.Decode(name, c.Value)
+}
+
+// SetSecureCookie protects the value of c with the codec and adds it to the response.
+//
+// Parameters:
+//   - codec: The cookie.Codec used to sign or encrypt the value.
+//   - c: The cookie to set, holding the plain value.
+//
+// Returns:
+//   - error: An error if the value could not be protected.
+func (ctx *Request[T]) SetSecureCookie(codec cookie.Codec, c *http.Cookie) error {
+	value, err := codec.Encode(c.Name, c.Value)
+	if err != nil {
+		return err
+	}
+
+	protected := *c
+	protected.Value = value
+	ctx.SetCookie(&protected)
+	return nil
+}
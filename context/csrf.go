@@ -0,0 +1,13 @@
+package context
+
+// CsrfTokenKey is the attribute key under which the CSRF middleware stores the token of the request.
+var CsrfTokenKey = NewKey[string]("goserve.csrf.token")
+
+// CsrfFieldKey is the attribute key under which the CSRF middleware stores the form field of the token.
+var CsrfFieldKey = NewKey[string]("goserve.csrf.field")
+
+// CsrfToken returns the CSRF token of the request, to be embedded in templated forms or sent back
+// by clients in the X-CSRF-Token header. It is empty when CSRF protection is not enabled.
+func (ctx *Request[T]) CsrfToken() string {
+	return GetOrElse(ctx, CsrfTokenKey, "")
+}
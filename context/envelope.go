@@ -0,0 +1,128 @@
+package context
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+// Enveloped holds the parts of an enveloped response. It is the body of the responses of the
+// default envelope strategy: `{"data": ..., "meta": {...}, "errors": [...]}`.
+type Enveloped struct {
+	Data   any            `json:"data,omitempty"`   // Data is the body of the successful responses.
+	Meta   map[string]any `json:"meta,omitempty"`   // Meta holds the members added by the MetaHook values and the pagination.
+	Errors []any          `json:"errors,omitempty"` // Errors holds the error of the error responses, such as a problem.
+}
+
+// ResponseInfo describes the response being enveloped, for MetaHook values.
+type ResponseInfo struct {
+	Request   *http.Request // Request is the HTTP request being answered.
+	SessionId string        // SessionId is the session id of the request.
+	Status    int           // Status is the status code of the response.
+	Body      any           // Body is the body given to Write, before being enveloped.
+	Elapsed   time.Duration // Elapsed is the time elapsed since the request started.
+}
+
+// MetaHook adds members to the meta of an enveloped response, such as the request id or timing.
+type MetaHook func(info ResponseInfo, meta map[string]any)
+
+// EnvelopeStrategy builds the body written for an enveloped response, so APIs with other envelope
+// shapes, such as `{"result", "metadata"}`, can map the parts to their own members.
+type EnvelopeStrategy func(response Enveloped, status int) any
+
+// Paginated is implemented by list bodies, such as query.Page, whose items are enveloped as data
+// and whose pagination members are added to the meta under `pagination`.
+type Paginated interface {
+	PageItems() any
+	PageMeta() map[string]any
+}
+
+// Envelope wraps the JSON bodies written by Request.Write, the successful responses and the errors
+// of goserve's own resources alike, so every response of an API has the same shape.
+//
+// Example usage:
+//
+//	api.Envelope(&goservectx.Envelope{
+//		Meta: []goservectx.MetaHook{goservectx.RequestIdMeta, goservectx.TimingMeta},
+//	})
+type Envelope struct {
+	Strategy EnvelopeStrategy // Strategy builds the response body. DataEnvelope when nil.
+	Meta     []MetaHook       // Meta are the hooks adding members to the meta of every response.
+}
+
+// DataEnvelope is the default EnvelopeStrategy, answering the `{data, meta, errors}` object.
+func DataEnvelope(response Enveloped, _ int) any {
+	return response
+}
+
+// RequestIdMeta adds the session id of the request as `requestId`.
+func RequestIdMeta(info ResponseInfo, meta map[string]any) {
+	meta["requestId"] = info.SessionId
+}
+
+// TimingMeta adds the time elapsed since the request started, in milliseconds, as `durationMs`.
+func TimingMeta(info ResponseInfo, meta map[string]any) {
+	meta["durationMs"] = float64(info.Elapsed.Microseconds()) / 1000
+}
+
+// UseEnvelope sets the envelope of the JSON responses written by this request, or disables it when
+// nil. It is usually configured globally or per route on the server, instead of being called by handlers.
+func (ctx *Request[T]) UseEnvelope(envelope *Envelope) {
+	ctx.envelope = envelope
+}
+
+// envelopeParts splits the body in the data, errors and pagination meta of an enveloped response.
+func envelopeParts(body any, status int) Enveloped {
+	var response Enveloped
+
+	switch typed := body.(type) {
+	case *problem.Problem:
+		response.Errors = []any{typed}
+	case Paginated:
+		response.Data = typed.PageItems()
+		response.Meta = map[string]any{"pagination": typed.PageMeta()}
+	default:
+		if status >= http.StatusBadRequest && body != nil {
+			response.Errors = []any{body}
+		} else {
+			response.Data = body
+		}
+	}
+	return response
+}
+
+// envelop returns the enveloped body, with data replaced by the filtered data, and the meta of
+// the hooks.
+func (ctx *Request[T]) envelop(response Enveloped, body any, status int) any {
+	if len(ctx.envelope.Meta) > 0 {
+		if response.Meta == nil {
+			response.Meta = make(map[string]any, len(ctx.envelope.Meta))
+		}
+
+		info := ResponseInfo{
+			Request:   ctx.Request,
+			SessionId: ctx.GetSessionId(),
+			Status:    status,
+			Body:      body,
+		}
+		if writer := ctx.ResponseWriter(); writer != nil {
+			info.Elapsed = writer.Elapsed()
+		}
+
+		for _, hook := range ctx.envelope.Meta {
+			hook(info, response.Meta)
+		}
+	}
+
+	if len(response.Errors) > 0 {
+		// The problem is a member of the envelope now, so the body is plain JSON.
+		(*ctx.Writer).Header().Set(ContentType, ApplicationJson)
+	}
+
+	strategy := ctx.envelope.Strategy
+	if strategy == nil {
+		strategy = DataEnvelope
+	}
+	return strategy(response, status)
+}
@@ -0,0 +1,97 @@
+package context
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/fieldset"
+	"github.com/softwareplace/goserve/problem"
+)
+
+type testPage struct {
+	Items []map[string]any
+	Total int
+}
+
+func (p testPage) PageItems() any {
+	return p.Items
+}
+
+func (p testPage) PageMeta() map[string]any {
+	return map[string]any{"total": p.Total}
+}
+
+func TestRequest_Envelope(t *testing.T) {
+	envelope := &Envelope{Meta: []MetaHook{RequestIdMeta, TimingMeta}}
+
+	decode := func(t *testing.T, body []byte) map[string]any {
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(body, &response))
+		return response
+	}
+
+	t.Run("should wrap successful bodies as data with the meta of the hooks", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseEnvelope(envelope)
+		ctx.Ok(map[string]any{"id": 1})
+
+		response := decode(t, rr.Body.Bytes())
+		require.Equal(t, map[string]any{"id": float64(1)}, response["data"])
+		require.NotContains(t, response, "errors")
+
+		meta := response["meta"].(map[string]any)
+		require.Equal(t, ctx.GetSessionId(), meta["requestId"])
+		require.Contains(t, meta, "durationMs")
+	})
+
+	t.Run("should wrap errors", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseEnvelope(envelope)
+		ctx.Problem(problem.NotFound("pet 1 does not exist"))
+
+		response := decode(t, rr.Body.Bytes())
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Equal(t, ApplicationJson, rr.Header().Get(ContentType))
+		require.NotContains(t, response, "data")
+
+		errors := response["errors"].([]any)
+		require.Len(t, errors, 1)
+		require.Equal(t, "pet 1 does not exist", errors[0].(map[string]any)["detail"])
+	})
+
+	t.Run("should move the pagination to the meta", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseEnvelope(&Envelope{})
+		ctx.UseFieldSelection(fieldset.Parse("id", ""))
+		ctx.Ok(testPage{Items: []map[string]any{{"id": 1, "name": "doggie"}}, Total: 1})
+
+		require.JSONEq(t, `{"data": [{"id": 1}], "meta": {"pagination": {"total": 1}}}`, rr.Body.String())
+	})
+
+	t.Run("should build the body with the strategy", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseEnvelope(&Envelope{Strategy: func(response Enveloped, status int) any {
+			return map[string]any{"result": response.Data, "status": status}
+		}})
+		ctx.Created("doggie")
+
+		require.JSONEq(t, `{"result": "doggie", "status": 201}`, rr.Body.String())
+	})
+
+	t.Run("should compute the ETag of the data only", func(t *testing.T) {
+		body := map[string]any{"id": 1}
+		expected, err := ETagOf(body, ETagStrong)
+		require.NoError(t, err)
+
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseEnvelope(envelope)
+		ctx.UseETag(ETagStrong)
+		ctx.Ok(body)
+
+		require.Equal(t, expected, rr.Header().Get("ETag"))
+		require.Contains(t, decode(t, rr.Body.Bytes()), "meta")
+	})
+}
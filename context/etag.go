@@ -0,0 +1,152 @@
+package context
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETagMode selects whether JSON responses get an ETag and how it is compared.
+type ETagMode int
+
+const (
+	ETagDisabled ETagMode = iota // ETagDisabled sends JSON responses without ETag, unless the handler sets one with SetETag.
+	ETagWeak                     // ETagWeak sends a weak ETag, such as `W/"3f2a..."`, computed from the response body.
+	ETagStrong                   // ETagStrong sends a strong ETag, such as `"3f2a..."`, computed from the response body.
+)
+
+// UseETag sets the ETag mode of the JSON responses written by this request. It is usually
+// configured globally or per route on the server, instead of being called by handlers.
+func (ctx *Request[T]) UseETag(mode ETagMode) {
+	ctx.etagMode = mode
+}
+
+// SetETag sets the ETag of the response, such as one derived from the version of the resource,
+// so that it is used instead of an ETag computed from the body. The tag must be quoted.
+func (ctx *Request[T]) SetETag(tag string) {
+	(*ctx.Writer).Header().Set("ETag", tag)
+}
+
+// ETagOf computes the ETag of the JSON representation of body, as the responses do.
+// It is useful to compare the current state of a resource with an If-Match header.
+//
+// Parameters:
+//   - body: The value whose JSON representation identifies the resource state.
+//   - mode: ETagWeak or ETagStrong.
+//
+// Returns:
+//   - string: The quoted ETag.
+//   - error: An error if the value could not be encoded.
+func ETagOf(body any, mode ETagMode) (string, error) {
+	var buffer bytes.Buffer
+	if err := encoder(&buffer, body); err != nil {
+		return "", err
+	}
+	return etagOf(buffer.Bytes(), mode), nil
+}
+
+// IfMatch checks the If-Match precondition of the request against the current ETag of the
+// resource, for optimistic concurrency in update handlers. When the precondition fails,
+// it answers 412 Precondition Failed.
+//
+// Parameters:
+//   - current: The current ETag of the resource, or an empty string if the resource does not exist.
+//
+// Returns:
+//   - bool: True if the request has no If-Match header or one of its tags matches; false otherwise.
+//
+// Example usage:
+//
+//	current, _ := goservectx.ETagOf(pet, goservectx.ETagStrong)
+//	if !ctx.IfMatch(current) {
+//		return
+//	}
+func (ctx *Request[T]) IfMatch(current string) bool {
+	header := ctx.Request.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+
+	if current != "" && matchesETag(header, current, false) {
+		return true
+	}
+
+	ctx.PreconditionFailed("The resource was modified: If-Match precondition failed")
+	return false
+}
+
+// PreconditionFailed sends an HTTP 412 Precondition Failed response with a given message.
+func (ctx *Request[T]) PreconditionFailed(message string) {
+	ctx.Error(message, http.StatusPreconditionFailed)
+}
+
+// writeWithETag writes the JSON payload with the ETag of the body, answering 304 Not Modified when
+// the If-None-Match header of a GET or HEAD request matches it. The payload is the body itself,
+// or its envelope, whose meta may change on every request and so is not part of the ETag.
+func (ctx *Request[T]) writeWithETag(body any, payload any, status int) error {
+	var buffer bytes.Buffer
+	if err := encoder(&buffer, body); err != nil {
+		return err
+	}
+
+	writer := *ctx.Writer
+	tag := writer.Header().Get("ETag")
+	if tag == "" {
+		tag = etagOf(buffer.Bytes(), ctx.etagMode)
+		writer.Header().Set("ETag", tag)
+	}
+
+	method := ctx.Request.Method
+	ifNoneMatch := ctx.Request.Header.Get("If-None-Match")
+	if (method == http.MethodGet || method == http.MethodHead) && ifNoneMatch != "" && matchesETag(ifNoneMatch, tag, true) {
+		writer.Header().Del(ContentType)
+		writer.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	if ctx.envelope != nil {
+		buffer.Reset()
+		if err := encoder(&buffer, payload); err != nil {
+			return err
+		}
+	}
+
+	writer.WriteHeader(status)
+	_, err := writer.Write(buffer.Bytes())
+	return err
+}
+
+func etagOf(content []byte, mode ETagMode) string {
+	hash := sha256.Sum256(content)
+	tag := `"` + hex.EncodeToString(hash[:16]) + `"`
+	if mode == ETagWeak {
+		return "W/" + tag
+	}
+	return tag
+}
+
+// matchesETag compares the tag with a list of tags from an If-Match or If-None-Match header.
+// If-None-Match uses the weak comparison, ignoring the W/ prefix, and If-Match the strong one.
+func matchesETag(header string, tag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	if !weak && strings.HasPrefix(tag, "W/") {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+				return true
+			}
+		} else if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
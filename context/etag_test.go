@@ -0,0 +1,78 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func etagRequest(method string, headers map[string]string) (*Request[*mockPrincipal], *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/pets/1", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	return Of[*mockPrincipal](recorder, req, "testReference"), recorder
+}
+
+func TestRequest_ETag(t *testing.T) {
+	body := map[string]any{"id": 1, "name": "doggie"}
+	strong, err := ETagOf(body, ETagStrong)
+	require.NoError(t, err)
+
+	t.Run("should not send an ETag when disabled", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.Ok(body)
+		assert.Empty(t, rr.Header().Get("ETag"))
+	})
+
+	t.Run("should send a weak or strong ETag", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseETag(ETagStrong)
+		ctx.Ok(body)
+		assert.Equal(t, strong, rr.Header().Get("ETag"))
+		assert.JSONEq(t, `{"id":1,"name":"doggie"}`, rr.Body.String())
+
+		ctx, rr = etagRequest(http.MethodGet, nil)
+		ctx.UseETag(ETagWeak)
+		ctx.Ok(body)
+		assert.Equal(t, "W/"+strong, rr.Header().Get("ETag"))
+	})
+
+	t.Run("should answer not modified when If-None-Match matches", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, map[string]string{"If-None-Match": `"other", W/` + strong})
+		ctx.UseETag(ETagStrong)
+		ctx.Ok(body)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+	})
+
+	t.Run("should prefer the ETag set by the handler", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, map[string]string{"If-None-Match": `"v2"`})
+		ctx.SetETag(`"v2"`)
+		ctx.Ok(body)
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+	})
+
+	t.Run("should check the If-Match precondition", func(t *testing.T) {
+		ctx, _ := etagRequest(http.MethodPut, nil)
+		assert.True(t, ctx.IfMatch(strong))
+
+		ctx, _ = etagRequest(http.MethodPut, map[string]string{"If-Match": strong})
+		assert.True(t, ctx.IfMatch(strong))
+
+		ctx, rr := etagRequest(http.MethodPut, map[string]string{"If-Match": `"stale"`})
+		assert.False(t, ctx.IfMatch(strong))
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+		ctx, _ = etagRequest(http.MethodPut, map[string]string{"If-Match": "W/" + strong})
+		assert.False(t, ctx.IfMatch("W/"+strong))
+
+		ctx, _ = etagRequest(http.MethodPut, map[string]string{"If-Match": "*"})
+		assert.False(t, ctx.IfMatch(""))
+	})
+}
@@ -0,0 +1,41 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/softwareplace/goserve/fieldset"
+)
+
+// UseFieldSelection sets the sparse fieldset applied to the successful JSON responses written by
+// this request. It is usually set by the server from the `?fields=` and `?exclude=` parameters
+// of the routes configured with server.WithFields, instead of being called by handlers.
+func (ctx *Request[T]) UseFieldSelection(selection *fieldset.Selection) {
+	ctx.fieldSelection = selection
+}
+
+// FieldSelection returns the sparse fieldset applied to the responses of the request, or nil
+// when every member is written. Handlers can use it to skip loading members that are not selected.
+func (ctx *Request[T]) FieldSelection() *fieldset.Selection {
+	return ctx.fieldSelection
+}
+
+// selectFields returns the body filtered by the field selection when it applies to the status,
+// or the body itself, also on error.
+func (ctx *Request[T]) selectFields(body any, status int) (any, error) {
+	if ctx.fieldSelection.IsEmpty() || body == nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return body, nil
+	}
+
+	var buffer bytes.Buffer
+	if err := encoder(&buffer, body); err != nil {
+		return body, err
+	}
+
+	filtered, err := ctx.fieldSelection.Filter(buffer.Bytes())
+	if err != nil {
+		return body, err
+	}
+	return json.RawMessage(filtered), nil
+}
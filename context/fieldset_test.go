@@ -0,0 +1,41 @@
+package context
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/fieldset"
+)
+
+func TestRequest_FieldSelection(t *testing.T) {
+	body := map[string]any{"id": 1, "name": "doggie", "owner": map[string]string{"name": "Ana", "email": "ana@example.com"}}
+
+	t.Run("should filter the successful responses", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseFieldSelection(fieldset.Parse("id,owner", "owner.email"))
+		ctx.Ok(body)
+
+		require.JSONEq(t, `{"id":1,"owner":{"name":"Ana"}}`, rr.Body.String())
+	})
+
+	t.Run("should compute the ETag of the filtered response", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseETag(ETagStrong)
+		ctx.UseFieldSelection(fieldset.Parse("id", ""))
+		ctx.Ok(body)
+
+		expected, err := ETagOf(map[string]any{"id": 1}, ETagStrong)
+		require.NoError(t, err)
+		require.Equal(t, expected, rr.Header().Get("ETag"))
+	})
+
+	t.Run("should not filter error responses", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseFieldSelection(fieldset.Parse("id", ""))
+		ctx.Response(body, http.StatusConflict)
+
+		require.Contains(t, rr.Body.String(), `"name":"doggie"`)
+	})
+}
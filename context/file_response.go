@@ -0,0 +1,176 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileOptions customizes a file response. See WriteContent.
+type FileOptions struct {
+	FileName     string    // FileName is the name proposed to the client and used to detect the content type by extension.
+	Inline       bool      // Inline asks the client to display the file, such as a video preview, instead of downloading it.
+	ContentType  string    // ContentType overrides the content type detected from the file name or content.
+	ModTime      time.Time // ModTime enables Last-Modified and If-Modified-Since/If-Unmodified-Since handling.
+	ETag         string    // ETag enables If-None-Match/If-Match/If-Range handling. It must be quoted, such as `"v1"` or `W/"v1"`.
+	CacheControl string    // CacheControl sets the Cache-Control header, such as `private, max-age=3600`.
+}
+
+// WriteContent streams content as the response, with the behaviour of http.ServeContent:
+// it sets Content-Length, serves Range and If-Range requests with 206 Partial Content,
+// answers If-None-Match and If-Modified-Since with 304 Not Modified, and HEAD requests
+// without body. The content type is taken from options.ContentType, the file name extension,
+// or detected from the content, in this order.
+//
+// Parameters:
+//   - content: The content to send. It must be seekable, so that ranges can be served.
+//   - options: The file name, disposition, content type and validators of the response.
+//
+// Returns:
+//   - error: An error if the content size could not be determined.
+//
+// Example usage:
+//
+//	video, _ := os.Open("preview.mp4")
+//	defer video.Close()
+//	err := ctx.WriteContent(video, goservectx.FileOptions{FileName: "preview.mp4", Inline: true, ModTime: modTime})
+func (ctx *Request[T]) WriteContent(content io.ReadSeeker, options FileOptions) error {
+	writer := *ctx.Writer
+	header := writer.Header()
+
+	// Drop the default JSON content type, so that ServeContent detects the real one.
+	header.Del(ContentType)
+	if options.ContentType != "" {
+		header.Set(ContentType, options.ContentType)
+	}
+
+	if options.FileName != "" {
+		header.Set("Content-Disposition", ContentDisposition(options.FileName, options.Inline))
+	} else if options.Inline {
+		header.Set("Content-Disposition", "inline")
+	}
+
+	if options.ETag != "" {
+		header.Set("ETag", options.ETag)
+	}
+
+	if options.CacheControl != "" {
+		header.Set("Cache-Control", options.CacheControl)
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	http.ServeContent(writer, ctx.Request, options.FileName, options.ModTime, content)
+	ctx.Done()
+	return nil
+}
+
+// ServeFile streams the file at path as the response, as WriteContent does. The modification time
+// and an ETag derived from the file size and modification time are used for conditional requests,
+// unless they are set in options. The file name defaults to the base name of path.
+//
+// Parameters:
+//   - path: The path of the file to send.
+//   - options: The file name, disposition, content type and validators of the response.
+//
+// Returns:
+//   - error: An error if the file could not be opened. Nothing was written to the response in that case.
+func (ctx *Request[T]) ServeFile(path string, options FileOptions) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+
+	if options.FileName == "" {
+		options.FileName = filepath.Base(path)
+	}
+	if options.ModTime.IsZero() {
+		options.ModTime = info.ModTime()
+	}
+	if options.ETag == "" {
+		options.ETag = fmt.Sprintf(`"%x-%x"`, info.Size(), info.ModTime().UnixNano())
+	}
+	return ctx.WriteContent(file, options)
+}
+
+// ContentDisposition formats a Content-Disposition header value for the file name. Names that are
+// not plain ASCII are encoded as RFC 5987 `filename*`, with an ASCII `filename` fallback for older clients.
+//
+// Parameters:
+//   - fileName: The name proposed to the client.
+//   - inline: True for `inline`, false for `attachment`.
+//
+// Returns:
+//   - string: The header value, such as `attachment; filename="report.pdf"`.
+func ContentDisposition(fileName string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	fallback, isPlain := asciiFileName(fileName)
+	value := fmt.Sprintf(`%s; filename="%s"`, disposition, fallback)
+	if !isPlain {
+		value += "; filename*=UTF-8''" + encodeRFC5987(fileName)
+	}
+	return value
+}
+
+// readerETag returns a strong ETag computed from the whole content of the reader.
+func readerETag(reader io.ReaderAt, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(reader, 0, size)); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, nil
+}
+
+// asciiFileName replaces the characters that cannot be sent in a quoted-string header parameter.
+func asciiFileName(fileName string) (string, bool) {
+	isPlain := true
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' || r == '%' {
+			isPlain = false
+			return '_'
+		}
+		return r
+	}, fileName)
+	return fallback, isPlain
+}
+
+func encodeRFC5987(value string) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		if isAttrChar(b) {
+			encoded.WriteByte(b)
+		} else {
+			_, _ = fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
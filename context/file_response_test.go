@@ -0,0 +1,73 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveFileRequest(t *testing.T, path string, options FileOptions, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/files", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	ctx := Of[*mockPrincipal](recorder, req, "testReference")
+	require.NoError(t, ctx.ServeFile(path, options))
+	return recorder
+}
+
+func TestRequest_ServeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("0123456789"), 0o600))
+
+	t.Run("should send the file with detected content type and length", func(t *testing.T) {
+		rr := serveFileRequest(t, path, FileOptions{}, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get(ContentType))
+		assert.Equal(t, "10", rr.Header().Get("Content-Length"))
+		assert.Equal(t, `attachment; filename="notes.txt"`, rr.Header().Get("Content-Disposition"))
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+		assert.Equal(t, "0123456789", rr.Body.String())
+	})
+
+	t.Run("should serve ranges", func(t *testing.T) {
+		rr := serveFileRequest(t, path, FileOptions{Inline: true}, map[string]string{"Range": "bytes=2-4"})
+
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "bytes 2-4/10", rr.Header().Get("Content-Range"))
+		assert.Equal(t, "234", rr.Body.String())
+		assert.Equal(t, `inline; filename="notes.txt"`, rr.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("should answer not modified for a matching ETag", func(t *testing.T) {
+		etag := serveFileRequest(t, path, FileOptions{}, nil).Header().Get("ETag")
+		rr := serveFileRequest(t, path, FileOptions{}, map[string]string{"If-None-Match": etag})
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+	})
+
+	t.Run("should ignore the range when If-Range does not match", func(t *testing.T) {
+		rr := serveFileRequest(t, path, FileOptions{}, map[string]string{"Range": "bytes=2-4", "If-Range": `"other"`})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "0123456789", rr.Body.String())
+	})
+}
+
+func TestContentDisposition(t *testing.T) {
+	assert.Equal(t, `attachment; filename="report.pdf"`, ContentDisposition("report.pdf", false))
+	assert.Equal(t,
+		`inline; filename="relat_rio _final_.pdf"; filename*=UTF-8''relat%C3%B3rio%20%22final%22.pdf`,
+		ContentDisposition(`relatório "final".pdf`, true),
+	)
+}
@@ -0,0 +1,32 @@
+package context
+
+import (
+	"github.com/softwareplace/goserve/i18n"
+)
+
+// Locale returns the locale of the request, selected from the Accept-Language header among the
+// locales of the i18n catalog. It falls back to i18n.DefaultLocale when the header is missing or
+// asks for unsupported languages.
+//
+// Returns:
+//   - string: The locale, such as en, pt-BR or es.
+func (ctx *Request[T]) Locale() string {
+	if ctx.locale == "" {
+		ctx.locale = i18n.Match(ctx.Request.Header.Get("Accept-Language"))
+	}
+	return ctx.locale
+}
+
+// Translate translates the message into the locale of the request. Messages without a translation
+// are returned as they are. See i18n.Catalog.Translate.
+//
+// Parameters:
+//   - key: The message key, usually the English message.
+//   - args: The values of the {0}, {1}, ... placeholders of the message.
+//
+// Example usage:
+//
+//	ctx.BadRequest(ctx.Translate("Pet {0} is not available", petId))
+func (ctx *Request[T]) Translate(key string, args ...string) string {
+	return i18n.Translate(ctx.Locale(), key, args...)
+}
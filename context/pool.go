@@ -0,0 +1,98 @@
+package context
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// pools holds a *sync.Pool of Request values for each principal type.
+var pools sync.Map
+
+// handle links an *http.Request to the Request that owns it. Release clears it, so a request kept
+// after the Request went back to the pool, such as by a goroutine, can't reach its next owner.
+type handle[T Principal] struct {
+	ctx *Request[T]
+}
+
+func poolOf[T Principal]() *sync.Pool {
+	key := reflect.TypeFor[T]()
+	if pool, ok := pools.Load(key); ok {
+		return pool.(*sync.Pool)
+	}
+
+	pool, _ := pools.LoadOrStore(key, &sync.Pool{
+		New: func() any {
+			return &Request[T]{}
+		},
+	})
+	return pool.(*sync.Pool)
+}
+
+// acquire returns a Request of the pool, reset and ready for a new request.
+func acquire[T Principal]() *Request[T] {
+	ctx := poolOf[T]().Get().(*Request[T])
+	ctx.pooled = true
+	return ctx
+}
+
+// Release resets the Request and returns it to the pool, to be reused by a later request.
+//
+// The Request is owned by the middleware that created it, which is rootAppMiddleware on a goserve
+// server: it releases the Request once the response is written. Handlers and middlewares must not
+// keep the Request, its Writer or its Request after they return, nor use them from goroutines that
+// outlive the request; copy the values they need, such as with GetSample, instead. The Attributes
+// are the exception: each request gets its own store, which goroutines may keep using.
+//
+// Release must be called once, by the owner: the Request may already belong to another request
+// after it. On a Request that was not taken from the pool, it only flushes it.
+func (ctx *Request[T]) Release() {
+	pooled := ctx.pooled
+	ctx.Flush()
+	if pooled {
+		poolOf[T]().Put(ctx)
+	}
+}
+
+// reset clears every field of the Request. The attribute store is dropped rather than cleared,
+// so goroutines started during the request keep their own attributes, and the next request gets
+// a new store.
+func (ctx *Request[T]) reset() {
+	if ctx.handle != nil {
+		ctx.handle.ctx = nil
+	}
+	*ctx = Request[T]{}
+}
+
+// Query returns the query parameters of the request URL. They are parsed on the first call and
+// kept in QueryValues, so handlers that never read them don't pay for the parsing.
+func (ctx *Request[T]) Query() url.Values {
+	if ctx.QueryValues == nil && ctx.Request != nil {
+		ctx.QueryValues = ctx.Request.URL.Query()
+	}
+	return ctx.QueryValues
+}
+
+// Vars returns the route variables of the request URL, as PathValues. They are read from the
+// router on the first call and kept in PathValues.
+func (ctx *Request[T]) Vars() map[string]string {
+	if ctx.PathValues == nil && ctx.Request != nil {
+		ctx.PathValues = mux.Vars(ctx.Request)
+	}
+	return ctx.PathValues
+}
+
+// wrapWriter wraps w into the ResponseWriter embedded in the Request, so it is pooled with it.
+func (ctx *Request[T]) wrapWriter(w http.ResponseWriter) {
+	if writer, ok := w.(ResponseWriter); ok {
+		ctx.writer = writer
+	} else {
+		ctx.responseWriter = responseWriter{ResponseWriter: w, start: time.Now()}
+		ctx.writer = &ctx.responseWriter
+	}
+	ctx.Writer = &ctx.writer
+}
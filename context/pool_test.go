@@ -0,0 +1,119 @@
+package context
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	poolTenantKey = NewKey[string]("tenant")
+	poolUserKey   = NewKey[string]("user")
+)
+
+func TestRequest_Release(t *testing.T) {
+	t.Run("should reset every field and drop the attribute store", func(t *testing.T) {
+		ctx := newMockContext()
+		ctx.Principal = new(*mockPrincipal)
+		ctx.Completed = true
+		ctx.locale = "pt-BR"
+		Set(ctx, poolTenantKey, "acme")
+		attributes := ctx.Attributes()
+
+		ctx.Release()
+
+		require.Nil(t, ctx.Writer)
+		require.Nil(t, ctx.Request)
+		require.Nil(t, ctx.Principal)
+		require.Nil(t, ctx.Headers)
+		require.False(t, ctx.Completed)
+		require.Empty(t, ctx.GetSessionId())
+		require.Empty(t, ctx.locale)
+		require.Nil(t, ctx.attributes)
+		require.NotSame(t, attributes, ctx.Attributes())
+	})
+
+	t.Run("should not share the attributes kept after the release with the next request", func(t *testing.T) {
+		ctx := newMockContext()
+		Set(ctx, poolTenantKey, "acme")
+		kept := ctx.Attributes()
+		ctx.Release()
+
+		next := newMockContext()
+		defer next.Release()
+		Set(next, poolTenantKey, "other")
+
+		tenant, _ := kept.get(poolTenantKey.id)
+		require.Equal(t, "acme", tenant)
+
+		kept.set(poolUserKey.id, "stale")
+		_, ok := Get(next, poolUserKey)
+		require.False(t, ok)
+	})
+
+	t.Run("should not return a released context for a request kept after the release", func(t *testing.T) {
+		ctx := newMockContext()
+		stale := ctx.Request
+		sessionId := ctx.GetSessionId()
+		ctx.Release()
+
+		next := Of[*mockPrincipal](httptest.NewRecorder(), stale, "testReference")
+		defer next.Release()
+
+		require.NotEmpty(t, next.GetSessionId())
+		require.NotEqual(t, sessionId, next.GetSessionId())
+	})
+
+	t.Run("should return the same context for the request it owns", func(t *testing.T) {
+		ctx := newMockContext()
+		defer ctx.Release()
+
+		require.Same(t, ctx, Of[*mockPrincipal](httptest.NewRecorder(), ctx.Request, "testReference"))
+	})
+
+	t.Run("should only flush contexts that are not pooled", func(t *testing.T) {
+		ctx := &Request[*mockPrincipal]{ApiKey: "key"}
+		ctx.Release()
+
+		require.Empty(t, ctx.ApiKey)
+		require.NotNil(t, ctx.Attributes())
+	})
+}
+
+func TestRequest_LazyValues(t *testing.T) {
+	ctx := newMockContext()
+	defer ctx.Release()
+
+	require.Nil(t, ctx.QueryValues)
+	require.Nil(t, ctx.PathValues)
+
+	require.Equal(t, "queryValue", ctx.QueryOf("queryKey"))
+	require.Equal(t, []string{"queryValue"}, ctx.QueryValues["queryKey"])
+
+	ctx.Request = mux.SetURLVars(ctx.Request, map[string]string{"id": "42"})
+	require.Equal(t, "42", ctx.PathValueOf("id"))
+	require.Equal(t, "42", ctx.PathValues["id"])
+	require.Equal(t, "42", ctx.GetSample().PathValues["id"])
+}
+
+func BenchmarkOf(b *testing.B) {
+	output := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() {
+		log.SetOutput(output)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/path?queryKey=queryValue", nil)
+	recorder := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		ctx := Of[*mockPrincipal](recorder, request, "benchmark")
+		ctx.Release()
+	}
+}
@@ -0,0 +1,89 @@
+package context
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/env"
+	"github.com/softwareplace/goserve/problem"
+)
+
+// ErrorFormat selects the body of the error responses.
+type ErrorFormat string
+
+const (
+	// ProblemFormat answers errors as RFC 7807 `application/problem+json`. It is the default format.
+	ProblemFormat ErrorFormat = "problem"
+	// LegacyFormat answers errors as `{"message", "statusCode", "timestamp"}` JSON objects.
+	LegacyFormat ErrorFormat = "legacy"
+)
+
+// DefaultErrorFormat returns the error format set by the ERROR_RESPONSE_FORMAT environment
+// variable, `problem` or `legacy`. It defaults to ProblemFormat.
+func DefaultErrorFormat() ErrorFormat {
+	if ErrorFormat(env.GetEnvOrDefault("ERROR_RESPONSE_FORMAT", string(ProblemFormat))) == LegacyFormat {
+		return LegacyFormat
+	}
+	return ProblemFormat
+}
+
+// UseErrorFormat sets the error format of this request, overriding DefaultErrorFormat. It is usually
+// configured on the server, instead of being called by handlers.
+func (ctx *Request[T]) UseErrorFormat(format ErrorFormat) {
+	ctx.errorFormat = format
+}
+
+// Problem answers the request with the error. Typed errors, such as problem.NotFound or
+// problem.Validation, are answered with their status, type and extension members, and any
+// other error with a 500 problem that does not expose the error message. The instance of
+// the problem is set to the session id of the request, and its title and detail are translated
+// into the request Locale when the i18n catalog has them.
+//
+// Parameters:
+//   - err: The error to answer with.
+//
+// Example usage:
+//
+//	pet, err := repository.Find(id)
+//	if err != nil {
+//		ctx.Problem(err) // repository returns problem.NotFound(...) when the pet does not exist
+//		return
+//	}
+func (ctx *Request[T]) Problem(err error) {
+	p := problem.From(err)
+	if p.Status >= http.StatusInternalServerError {
+		log.Errorf("[%s]:: request failed: %v", ctx.GetSessionId(), err)
+	}
+	ctx.writeProblem(p)
+}
+
+func (ctx *Request[T]) writeProblem(p *problem.Problem) {
+	if ctx.Completed || ctx.HeadersSent() {
+		log.Warnf("[%s]:: response already sent, discarding error: %v", ctx.GetSessionId(), p)
+		return
+	}
+
+	// goserve's own messages, and the messages added to the i18n catalog, answer in the request locale.
+	p.Title = ctx.Translate(p.Title)
+	p.Detail = ctx.Translate(p.Detail)
+	(*ctx.Writer).Header().Set("Content-Language", ctx.Locale())
+
+	if ctx.errorFormat == LegacyFormat || (ctx.errorFormat == "" && DefaultErrorFormat() == LegacyFormat) {
+		body := map[string]any{}
+		for key, value := range p.Extensions {
+			body[key] = value
+		}
+		body["message"] = p.Message()
+		body["statusCode"] = p.Status
+		body["timestamp"] = time.Now().UnixMilli()
+
+		ctx.Response(body, p.Status)
+		return
+	}
+
+	p.Instance = ctx.GetSessionId()
+	(*ctx.Writer).Header().Set(ContentType, problem.ContentType)
+	ctx.Response(p, p.Status)
+}
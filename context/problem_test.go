@@ -0,0 +1,77 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+func TestRequest_Problem(t *testing.T) {
+	t.Run("should answer typed errors as problem+json", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		ctx := newMockContextForRecorder(recorder)
+		ctx.Problem(problem.NotFound("pet 42 does not exist"))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, problem.ContentType, recorder.Header().Get(ContentType))
+		assert.Equal(t, "pet 42 does not exist", body["detail"])
+		assert.Equal(t, ctx.GetSessionId(), body["instance"])
+	})
+
+	t.Run("should answer untyped errors as internal server error", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		newMockContextForRecorder(recorder).Problem(errors.New("secret failure"))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), "secret failure")
+	})
+
+	t.Run("should answer in the legacy format when selected", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		ctx := newMockContextForRecorder(recorder)
+		ctx.UseErrorFormat(LegacyFormat)
+		ctx.Problem(problem.Conflict("pet already exists"))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, ApplicationJson, recorder.Header().Get(ContentType))
+		assert.Equal(t, "pet already exists", body["message"])
+		assert.Equal(t, float64(http.StatusConflict), body["statusCode"])
+		assert.NotNil(t, body["timestamp"])
+	})
+
+	t.Run("should read the legacy format from the environment", func(t *testing.T) {
+		t.Setenv("ERROR_RESPONSE_FORMAT", "legacy")
+
+		recorder := httptest.NewRecorder()
+		newMockContextForRecorder(recorder).BadRequest("Invalid input")
+		assert.Contains(t, recorder.Body.String(), `"message":"Invalid input"`)
+	})
+}
+
+func TestRequest_Problem_Locale(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ctx := newMockContextForRecorder(recorder)
+	ctx.Request.Header.Set("Accept-Language", "es-MX,es;q=0.9,en;q=0.5")
+	ctx.Unauthorized()
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+	assert.Equal(t, "es", ctx.Locale())
+	assert.Equal(t, "es", recorder.Header().Get("Content-Language"))
+	assert.Equal(t, "No autorizado", body["detail"])
+	assert.Equal(t, "No autorizado", body["title"])
+}
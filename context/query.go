@@ -0,0 +1,45 @@
+package context
+
+import (
+	"strconv"
+
+	"github.com/softwareplace/goserve/query"
+)
+
+// ListQuery parses the pagination, sort and filter parameters of a list request, validating
+// the sort and filter fields against the whitelist of options. See query.Parse for the syntax.
+//
+// Parameters:
+//   - options: The whitelisted fields and page size limits of the endpoint.
+//
+// Returns:
+//   - query.Query: The parsed query.
+//   - error: A *query.Error if a parameter is invalid, answered as a 400 problem by Problem.
+//
+// Example usage:
+//
+//	q, err := ctx.ListQuery(query.Options{SortFields: []string{"name"}, FilterFields: []string{"status"}})
+//	if err != nil {
+//		ctx.Problem(err)
+//		return
+//	}
+//	pets, total := repository.Find(q)
+//	ctx.WritePage(query.NewPage(pets, total, q))
+func (ctx *Request[T]) ListQuery(options query.Options) (query.Query, error) {
+	return query.Parse(ctx.Query(), options)
+}
+
+// WritePage sends a list envelope, such as query.NewPage or query.NewCursorPage, with a 200 status.
+// It sets the Link header with the first, prev, next and last pages and, when the total is known,
+// the X-Total-Count header.
+func (ctx *Request[T]) WritePage(page query.Envelope) {
+	header := (*ctx.Writer).Header()
+
+	if link := page.LinkHeader(ctx.Request.URL); link != "" {
+		header.Set("Link", link)
+	}
+	if total, ok := page.TotalCount(); ok {
+		header.Set("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+	ctx.Ok(page)
+}
@@ -0,0 +1,44 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/query"
+)
+
+func TestRequest_WritePage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pets?page=1&size=2&filter=status+eq+available", nil)
+	recorder := httptest.NewRecorder()
+	ctx := Of[*mockPrincipal](recorder, req, "testReference")
+
+	q, err := ctx.ListQuery(query.Options{FilterFields: []string{"status"}})
+	require.NoError(t, err)
+	require.Len(t, q.Filter, 1)
+
+	ctx.WritePage(query.NewPage([]string{"a", "b"}, 5, q))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "5", recorder.Header().Get("X-Total-Count"))
+	assert.Contains(t, recorder.Header().Get("Link"), `rel="next"`)
+	assert.JSONEq(t, `{"items":["a","b"],"page":1,"size":2,"total":5,"totalPages":3}`, recorder.Body.String())
+}
+
+func TestRequest_ListQueryProblem(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pets?sort=password", nil)
+	recorder := httptest.NewRecorder()
+	ctx := Of[*mockPrincipal](recorder, req, "testReference")
+
+	_, err := ctx.ListQuery(query.Options{SortFields: []string{"name"}})
+	require.Error(t, err)
+
+	ctx.Problem(err)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"parameter":"sort"`)
+	assert.Contains(t, recorder.Body.String(), `is not sortable`)
+}
@@ -29,8 +29,8 @@ func (ctx *Request[T]) GetSessionId() string {
 // Returns:
 //   - The first value of the query parameter or an empty string if it does not exist.
 func (ctx *Request[T]) QueryOf(key string) string {
-	if len(ctx.QueryValues[key]) > 0 {
-		return ctx.QueryValues[key][0]
+	if len(ctx.Query()[key]) > 0 {
+		return ctx.Query()[key][0]
 	}
 	return ""
 }
@@ -44,7 +44,7 @@ func (ctx *Request[T]) QueryOf(key string) string {
 // Returns:
 //   - A slice of strings containing all values of the query parameter or an empty slice if it does not exist.
 func (ctx *Request[T]) QueriesOf(key string) []string {
-	return ctx.QueryValues[key]
+	return ctx.Query()[key]
 }
 
 // QueriesOfElse retrieves all values of the specified query parameter from the request URL.
@@ -57,8 +57,8 @@ func (ctx *Request[T]) QueriesOf(key string) []string {
 // Returns:
 //   - A slice of strings containing all values of the query parameter or the default values.
 func (ctx *Request[T]) QueriesOfElse(key string, defaultQueries []string) []string {
-	if len(ctx.QueryValues[key]) > 0 {
-		return ctx.QueryValues[key]
+	if len(ctx.Query()[key]) > 0 {
+		return ctx.Query()[key]
 	}
 	return defaultQueries
 }
@@ -73,8 +73,8 @@ func (ctx *Request[T]) QueriesOfElse(key string, defaultQueries []string) []stri
 // Returns:
 //   - The first value of the query parameter or the default value.
 func (ctx *Request[T]) QueryOfOrElse(key string, defaultQuery string) string {
-	if len(ctx.QueryValues[key]) > 0 {
-		return ctx.QueryValues[key][0]
+	if len(ctx.Query()[key]) > 0 {
+		return ctx.Query()[key][0]
 	}
 	return defaultQuery
 }
@@ -147,7 +147,7 @@ func (ctx *Request[T]) HeaderOfOrElse(key string, defaultHeader string) string {
 // Returns:
 //   - The value of the path variable or an empty string if it does not exist.
 func (ctx *Request[T]) PathValueOf(key string) string {
-	return ctx.PathValues[key]
+	return ctx.Vars()[key]
 }
 
 // FormValue retrieves the first value for the given form field name from the parsed form data.
@@ -173,7 +173,8 @@ func (ctx *Request[T]) FormValue(name string) any {
 //   - *multipart.FileHeader: The file header, or nil if an error occurs.
 //   - error: An error, if one occurs while retrieving the file.
 //
-// Make sure to close the file when no need it anymore.
+// Make sure to close the file when no need it anymore. The whole form is parsed before the file is
+// returned, so prefer Upload for large or untrusted uploads.
 func (ctx *Request[T]) FormFile(name string) (multipart.File, *multipart.FileHeader, error) {
 	file, fileHeader, err := ctx.Request.FormFile(name)
 	if err != nil {
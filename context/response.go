@@ -2,10 +2,9 @@ package context
 
 import (
 	"bytes"
-	"fmt"
-	"io"
 	"net/http"
-	"time"
+
+	"github.com/softwareplace/goserve/problem"
 )
 
 // InternalServerError sends an HTTP 500 Internal Server Error response with a given message.
@@ -53,16 +52,10 @@ func (ctx *Request[T]) NotFount(body any) {
 	ctx.Response(body, http.StatusNotFound)
 }
 
-// Error sends an HTTP error response with a status and a message. The response includes
-// the timestamp and status code for debugging or informational purposes.
+// Error sends an HTTP error response with a status and a message, as an RFC 7807 problem
+// whose detail is the message, or in the legacy format. See ErrorFormat.
 func (ctx *Request[T]) Error(message string, status int) {
-	responseBody := map[string]interface{}{
-		"message":    message,
-		"statusCode": status,
-		"timestamp":  time.Now().UnixMilli(),
-	}
-
-	ctx.Response(responseBody, status)
+	ctx.writeProblem(problem.New(status, "", message))
 }
 
 // Response sends a generic HTTP response with a given body and status code.
@@ -71,28 +64,22 @@ func (ctx *Request[T]) Response(body any, status int) {
 	ctx.Write(body, status)
 }
 
-// WriteFile streams a file as a response for download, using a given file name.
-// Sets appropriate headers for file attachment and streams the content.
+// WriteFile sends the file content as a download with the given file name. The content type is
+// detected from the file name or content, and a strong ETag computed from the content enables
+// conditional and Range requests. See WriteContent for more control over the response.
 func (ctx *Request[T]) WriteFile(file []byte, fileName string) error {
-	writer := *ctx.Writer
-
-	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
-	writer.Header().Set("Content-Type", "application/octet-stream")
-
-	_, err := io.Copy(writer, bytes.NewReader(file))
-	ctx.Done()
-	return err
+	return ctx.WriteReader(bytes.NewReader(file), fileName)
 }
 
-// WriteReader streams a file-like reader as a response for download with a given file name.
-// Sets appropriate headers for file attachment and streams the reader's content.
+// WriteReader sends the reader content as a download with the given file name, as WriteFile does.
 func (ctx *Request[T]) WriteReader(reader *bytes.Reader, fileName string) error {
-	writer := *ctx.Writer
-
-	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
-	writer.Header().Set("Content-Type", "application/octet-stream")
+	etag, err := readerETag(reader, reader.Size())
+	if err != nil {
+		return err
+	}
 
-	_, err := io.Copy(writer, reader)
-	ctx.Done()
-	return err
+	return ctx.WriteContent(reader, FileOptions{
+		FileName: fileName,
+		ETag:     etag,
+	})
 }
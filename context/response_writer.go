@@ -0,0 +1,153 @@
+package context
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ResponseWriter wraps the http.ResponseWriter of a Request and records the final status code,
+// the number of bytes written and the time to first byte, so that middlewares can log and measure
+// responses after the handler returns. It still exposes http.Flusher and http.Hijacker, and
+// Unwrap gives access to the original writer, also for http.ResponseController.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+
+	// Status returns the status code sent to the client, or zero if the headers were not sent yet.
+	Status() int
+
+	// Size returns the number of body bytes written to the client.
+	Size() int64
+
+	// Written reports whether the headers were already sent to the client.
+	Written() bool
+
+	// TimeToFirstByte returns the time elapsed between the creation of the writer and the moment
+	// the headers were sent, or zero if they were not sent yet.
+	TimeToFirstByte() time.Duration
+
+	// Elapsed returns the time elapsed since the creation of the writer, when the request started.
+	Elapsed() time.Duration
+
+	// Unwrap returns the original http.ResponseWriter.
+	Unwrap() http.ResponseWriter
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status    int
+	size      int64
+	written   bool
+	start     time.Time
+	firstByte time.Duration
+}
+
+// NewResponseWriter wraps w into a ResponseWriter. If w already is a ResponseWriter, it is returned as is.
+func NewResponseWriter(w http.ResponseWriter) ResponseWriter {
+	if rw, ok := w.(ResponseWriter); ok {
+		return rw
+	}
+
+	return &responseWriter{
+		ResponseWriter: w,
+		start:          time.Now(),
+	}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// Informational responses may be sent several times before the final one.
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if w.written {
+		log.Warnf("superfluous WriteHeader call with status %d ignored, status %d was already sent", status, w.status)
+		return
+	}
+
+	w.markWritten(status)
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// ReadFrom keeps the io.ReaderFrom optimisations, such as sendfile, of the original writer.
+func (w *responseWriter) ReadFrom(reader io.Reader) (int64, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	var n int64
+	var err error
+	if readerFrom, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = readerFrom.ReadFrom(reader)
+	} else {
+		n, err = io.Copy(w.ResponseWriter, reader)
+	}
+
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if err := http.NewResponseController(w.ResponseWriter).Flush(); err != nil {
+		log.Debugf("response writer flush not supported: %v", err)
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buffer, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && !w.written {
+		w.markWritten(http.StatusSwitchingProtocols)
+	}
+	return conn, buffer, err
+}
+
+func (w *responseWriter) markWritten(status int) {
+	w.written = true
+	w.status = status
+	w.firstByte = time.Since(w.start)
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int64 {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.written
+}
+
+func (w *responseWriter) Elapsed() time.Duration {
+	return time.Since(w.start)
+}
+
+func (w *responseWriter) TimeToFirstByte() time.Duration {
+	return w.firstByte
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
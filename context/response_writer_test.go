@@ -0,0 +1,62 @@
+package context
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseWriter_RecordsStatusAndSize(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ctx := newMockContextForRecorder(recorder)
+
+	require.False(t, ctx.HeadersSent())
+	require.Equal(t, 0, ctx.ResponseStatus())
+
+	ctx.Write(map[string]string{"key": "value"}, http.StatusCreated)
+
+	assert.True(t, ctx.HeadersSent())
+	assert.Equal(t, http.StatusCreated, ctx.ResponseStatus())
+	assert.Equal(t, int64(recorder.Body.Len()), ctx.ResponseSize())
+	assert.Greater(t, ctx.TimeToFirstByte().Nanoseconds(), int64(0))
+}
+
+func TestResponseWriter_DefaultsToOkOnWrite(t *testing.T) {
+	writer := NewResponseWriter(httptest.NewRecorder())
+
+	_, err := writer.Write([]byte("body"))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, writer.Status())
+	assert.Equal(t, int64(4), writer.Size())
+}
+
+func TestResponseWriter_IgnoresSuperfluousWriteHeader(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := NewResponseWriter(recorder)
+
+	writer.WriteHeader(http.StatusAccepted)
+	writer.WriteHeader(http.StatusInternalServerError)
+
+	assert.Equal(t, http.StatusAccepted, writer.Status())
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func TestResponseWriter_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writer := NewResponseWriter(recorder)
+
+	writer.Flush()
+
+	assert.True(t, recorder.Flushed)
+	assert.True(t, writer.Written())
+	assert.Same(t, recorder, writer.Unwrap())
+}
+
+func TestResponseWriter_DoesNotWrapTwice(t *testing.T) {
+	writer := NewResponseWriter(httptest.NewRecorder())
+	assert.Same(t, writer, NewResponseWriter(writer))
+}
@@ -0,0 +1,423 @@
+package context
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Stream formats.
+const (
+	StreamFormatCSV       = "csv"
+	StreamFormatNDJSON    = "ndjson"
+	StreamFormatJSONArray = "json"
+
+	// DefaultFlushRows is the number of rows written between two flushes when StreamOptions.FlushRows is not set.
+	DefaultFlushRows = 100
+
+	// DefaultFlushInterval is the longest time rows are buffered when StreamOptions.FlushInterval is not set.
+	DefaultFlushInterval = time.Second
+)
+
+// streamMetrics publishes, through expvar, the streamed rows and streams by format, and the
+// streams interrupted by the client or an error, under goserve_streams.
+var streamMetrics = expvar.NewMap("goserve_streams")
+
+// StreamOptions customizes a streamed response.
+type StreamOptions struct {
+	FileName      string                  // FileName sends the stream as a download with this name, when set.
+	FlushRows     int                     // FlushRows is the number of rows written between two flushes. Defaults to DefaultFlushRows.
+	FlushInterval time.Duration           // FlushInterval is the longest time rows are buffered. Defaults to DefaultFlushInterval.
+	OnComplete    func(stats StreamStats) // OnComplete receives the stats of the stream once it ends, such as to record metrics.
+}
+
+// StreamStats describes a finished stream.
+type StreamStats struct {
+	Format   string        // Format is csv, ndjson or json.
+	Rows     int64         // Rows is the number of rows written.
+	Bytes    int64         // Bytes is the number of body bytes written.
+	Duration time.Duration // Duration is the time spent streaming.
+	Err      error         // Err is the error that interrupted the stream, such as context.Canceled when the client disconnected.
+}
+
+// StreamCSV writes the rows as CSV, one row at a time, flushing them to the client periodically.
+// Struct rows get a header row with their `csv` tags, or their `json` tags or field names, and
+// fields tagged `csv:"-"` are skipped. []string rows are written as they are, without header.
+// The stream stops when the client disconnects or the request deadline is exceeded.
+//
+// Parameters:
+//   - ctx: The request context.
+//   - rows: The rows, usually read from a database cursor. See SeqOf to stream from a channel.
+//   - options: Optional StreamOptions.
+//
+// Returns:
+//   - int64: The number of rows written.
+//   - error: The error that interrupted the stream. The response was already started, so it is
+//     only logged and returned.
+//
+// Example usage:
+//
+//	func (s *Service) ExportOrders(ctx *goservectx.Request[*Principal]) {
+//		goservectx.StreamCSV(ctx, s.repository.AllOrders(ctx.Context()), goservectx.StreamOptions{
+//			FileName: "orders.csv",
+//		})
+//	}
+func StreamCSV[T Principal, E any](ctx *Request[T], rows iter.Seq[E], options ...StreamOptions) (int64, error) {
+	var (
+		writer  *csv.Writer
+		columns []csvColumn
+	)
+
+	return stream(ctx, rows, StreamFormatCSV, "text/csv; charset=utf-8", optionsOf(options), streamWriter[E]{
+		begin: func(w io.Writer) error {
+			writer = csv.NewWriter(w)
+			columns = csvColumnsOf(reflect.TypeFor[E]())
+			if columns == nil {
+				return nil
+			}
+
+			// The header is written even without rows, so empty exports are still valid CSV files.
+			if err := writer.Write(csvHeader(columns)); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		},
+		row: func(w io.Writer, row E, _ int64) error {
+			if err := writer.Write(csvRecord(reflect.ValueOf(&row).Elem(), columns)); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		},
+	})
+}
+
+// StreamNDJSON writes the rows as newline delimited JSON, one JSON value per line, as StreamCSV does.
+func StreamNDJSON[T Principal, E any](ctx *Request[T], rows iter.Seq[E], options ...StreamOptions) (int64, error) {
+	var encoder *json.Encoder
+
+	return stream(ctx, rows, StreamFormatNDJSON, "application/x-ndjson", optionsOf(options), streamWriter[E]{
+		begin: func(w io.Writer) error {
+			encoder = json.NewEncoder(w)
+			return nil
+		},
+		row: func(w io.Writer, row E, _ int64) error {
+			return encoder.Encode(row)
+		},
+	})
+}
+
+// StreamJSONArray writes the rows as a JSON array, one item at a time, as StreamCSV does, so large
+// lists are never held in memory. Clients see an unterminated array if the stream is interrupted.
+func StreamJSONArray[T Principal, E any](ctx *Request[T], rows iter.Seq[E], options ...StreamOptions) (int64, error) {
+	return stream(ctx, rows, StreamFormatJSONArray, ApplicationJson, optionsOf(options), streamWriter[E]{
+		begin: func(w io.Writer) error {
+			_, err := io.WriteString(w, "[")
+			return err
+		},
+		row: func(w io.Writer, row E, index int64) error {
+			if index > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+
+			item, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(item)
+			return err
+		},
+		end: func(w io.Writer) error {
+			_, err := io.WriteString(w, "]\n")
+			return err
+		},
+	})
+}
+
+// SeqOf returns an iterator over the values received from the channel until it is closed, to stream
+// rows produced by another goroutine. The producer should stop when ctx.Context() is done, since
+// the stream stops reading when the client disconnects.
+func SeqOf[E any](channel <-chan E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for value := range channel {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+type streamWriter[E any] struct {
+	begin func(w io.Writer) error
+	row   func(w io.Writer, row E, index int64) error
+	end   func(w io.Writer) error
+}
+
+func optionsOf(options []StreamOptions) StreamOptions {
+	var result StreamOptions
+	if len(options) > 0 {
+		result = options[0]
+	}
+	if result.FlushRows <= 0 {
+		result.FlushRows = DefaultFlushRows
+	}
+	if result.FlushInterval <= 0 {
+		result.FlushInterval = DefaultFlushInterval
+	}
+	return result
+}
+
+func stream[T Principal, E any](
+	ctx *Request[T],
+	rows iter.Seq[E],
+	format string,
+	contentType string,
+	options StreamOptions,
+	writer streamWriter[E],
+) (int64, error) {
+	started := time.Now()
+	response := *ctx.Writer
+
+	header := response.Header()
+	header.Set(ContentType, contentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	if options.FileName != "" {
+		header.Set("Content-Disposition", ContentDisposition(options.FileName, false))
+	}
+	response.WriteHeader(http.StatusOK)
+	ctx.Done()
+
+	counter := &countingWriter{writer: response}
+	buffered := bufio.NewWriter(counter)
+	flush := func() error {
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := response.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	var count int64
+	err := writer.begin(buffered)
+
+	if err == nil {
+		lastFlush := time.Now()
+		for row := range rows {
+			if err = ctx.Context().Err(); err != nil {
+				break
+			}
+
+			if err = writer.row(buffered, row, count); err != nil {
+				break
+			}
+			count++
+
+			if count%int64(options.FlushRows) == 0 || time.Since(lastFlush) >= options.FlushInterval {
+				if err = flush(); err != nil {
+					break
+				}
+				lastFlush = time.Now()
+			}
+		}
+	}
+
+	if err == nil && writer.end != nil {
+		err = writer.end(buffered)
+	}
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+
+	stats := StreamStats{
+		Format:   format,
+		Rows:     count,
+		Bytes:    counter.size,
+		Duration: time.Since(started),
+		Err:      err,
+	}
+	reportStream(ctx.GetSessionId(), stats)
+
+	if options.OnComplete != nil {
+		options.OnComplete(stats)
+	}
+	return count, err
+}
+
+func reportStream(sessionId string, stats StreamStats) {
+	streamMetrics.Add(stats.Format+".streams", 1)
+	streamMetrics.Add(stats.Format+".rows", stats.Rows)
+
+	if stats.Err != nil {
+		streamMetrics.Add(stats.Format+".interrupted", 1)
+		log.Warnf("[%s]:: STREAM/%s: interrupted after %d rows, %d bytes in %s: %v",
+			sessionId, strings.ToUpper(stats.Format), stats.Rows, stats.Bytes, stats.Duration, stats.Err)
+		return
+	}
+
+	log.Infof("[%s]:: STREAM/%s: wrote %d rows, %d bytes in %s",
+		sessionId, strings.ToUpper(stats.Format), stats.Rows, stats.Bytes, stats.Duration)
+}
+
+type countingWriter struct {
+	writer io.Writer
+	size   int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// csvColumn is a column of a struct row, at the index of its field.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+var csvColumnsCache sync.Map // map[reflect.Type][]csvColumn
+
+// csvColumnsOf returns the columns of struct rows, or nil for rows written as they are.
+func csvColumnsOf(t reflect.Type) []csvColumn {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	if columns, ok := csvColumnsCache.Load(t); ok {
+		return columns.([]csvColumn)
+	}
+
+	columns := structColumns(t, nil)
+	csvColumnsCache.Store(t, columns)
+	return columns
+}
+
+func structColumns(t reflect.Type, prefix []int) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, prefix...), i)
+
+		name, tagged := field.Tag.Lookup("csv")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			columns = append(columns, structColumns(field.Type, index)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name = strings.Split(name, ",")[0]
+		if name == "" {
+			name = strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+	return columns
+}
+
+func csvHeader(columns []csvColumn) []string {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	return header
+}
+
+func csvRecord(value reflect.Value, columns []csvColumn) []string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return make([]string, len(columns))
+		}
+		value = value.Elem()
+	}
+
+	if columns == nil {
+		if record, ok := value.Interface().([]string); ok {
+			return record
+		}
+		return []string{csvValue(value)}
+	}
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		field, err := value.FieldByIndexErr(column.index)
+		if err == nil {
+			record[i] = csvValue(field)
+		}
+	}
+	return record
+}
+
+func csvValue(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	if value.CanInterface() {
+		switch typed := value.Interface().(type) {
+		case time.Time:
+			return typed.Format(time.RFC3339)
+		case encoding.TextMarshaler:
+			text, err := typed.MarshalText()
+			if err == nil {
+				return string(text)
+			}
+		case fmt.Stringer:
+			return typed.String()
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		encoded, err := json.Marshal(value.Interface())
+		if err == nil {
+			return string(encoded)
+		}
+	}
+	return fmt.Sprint(value.Interface())
+}
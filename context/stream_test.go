@@ -0,0 +1,144 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type exportRow struct {
+	Id        int       `json:"id"`
+	Name      string    `csv:"full_name"`
+	Secret    string    `csv:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	Price     *float64
+	Tags      []string `json:"tags"`
+	internal  string
+}
+
+func exportRows() []exportRow {
+	price := 9.5
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return []exportRow{
+		{Id: 1, Name: "Rex, the dog", Secret: "x", CreatedAt: createdAt, Price: &price, Tags: []string{"a"}},
+		{Id: 2, Name: `Max "Jr"`, CreatedAt: createdAt},
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	t.Run("should write a header from the struct tags and one record per row", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		var stats StreamStats
+		count, err := StreamCSV(ctx, slices.Values(exportRows()), StreamOptions{
+			FileName:   "export.csv",
+			OnComplete: func(s StreamStats) { stats = s },
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+		require.Equal(t, "text/csv; charset=utf-8", rr.Header().Get(ContentType))
+		require.Contains(t, rr.Header().Get("Content-Disposition"), `attachment; filename="export.csv"`)
+		require.Equal(t, "id,full_name,createdAt,Price,tags\n"+
+			"1,\"Rex, the dog\",2024-05-01T10:00:00Z,9.5,\"[\"\"a\"\"]\"\n"+
+			"2,\"Max \"\"Jr\"\"\",2024-05-01T10:00:00Z,,null\n", rr.Body.String())
+
+		require.Equal(t, StreamFormatCSV, stats.Format)
+		require.Equal(t, int64(2), stats.Rows)
+		require.Equal(t, int64(rr.Body.Len()), stats.Bytes)
+		require.True(t, ctx.Completed)
+	})
+
+	t.Run("should write the header of empty exports", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		count, err := StreamCSV(ctx, slices.Values([]exportRow{}))
+
+		require.NoError(t, err)
+		require.Zero(t, count)
+		require.Equal(t, "id,full_name,createdAt,Price,tags\n", rr.Body.String())
+	})
+
+	t.Run("should write string rows as they are", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		_, err := StreamCSV(ctx, slices.Values([][]string{{"a", "b"}, {"c", "d"}}))
+
+		require.NoError(t, err)
+		require.Equal(t, "a,b\nc,d\n", rr.Body.String())
+	})
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	ctx, rr := etagRequest(http.MethodGet, nil)
+	rows := make(chan map[string]int)
+	go func() {
+		defer close(rows)
+		for i := 1; i <= 3; i++ {
+			rows <- map[string]int{"id": i}
+		}
+	}()
+
+	count, err := StreamNDJSON(ctx, SeqOf(rows), StreamOptions{FlushRows: 1})
+
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+	require.Equal(t, "application/x-ndjson", rr.Header().Get(ContentType))
+	require.Equal(t, "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n", rr.Body.String())
+	require.True(t, rr.Flushed)
+}
+
+func TestStreamJSONArray(t *testing.T) {
+	t.Run("should write a JSON array", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		count, err := StreamJSONArray(ctx, slices.Values([]int{1, 2, 3}))
+
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+		require.JSONEq(t, `[1, 2, 3]`, rr.Body.String())
+	})
+
+	t.Run("should write an empty array without rows", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		_, err := StreamJSONArray(ctx, slices.Values([]int{}))
+
+		require.NoError(t, err)
+		require.JSONEq(t, `[]`, rr.Body.String())
+	})
+}
+
+func TestStream_ClientDisconnect(t *testing.T) {
+	requestContext, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(requestContext)
+	rr := httptest.NewRecorder()
+	ctx := Of[*mockPrincipal](rr, req, "testReference")
+
+	var rows iter.Seq[int] = func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if i == 5 {
+				cancel()
+			}
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	var stats StreamStats
+	count, err := StreamNDJSON(ctx, rows, StreamOptions{OnComplete: func(s StreamStats) { stats = s }})
+
+	require.True(t, errors.Is(err, context.Canceled))
+	require.Equal(t, int64(5), count)
+	require.Equal(t, 5, strings.Count(rr.Body.String(), "\n"))
+	require.ErrorIs(t, stats.Err, context.Canceled)
+}
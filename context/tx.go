@@ -0,0 +1,22 @@
+package context
+
+import (
+	"database/sql"
+)
+
+// UseTx sets the database/sql transaction of the request. It is usually opened on the server
+// with Api.Transactions, instead of being set by handlers.
+func (ctx *Request[T]) UseTx(tx *sql.Tx) {
+	ctx.tx = tx
+}
+
+// Tx returns the database/sql transaction of the request, or nil when the route has none. The
+// server commits it once the handler answers a status below 400 and rolls it back otherwise, so
+// handlers must neither commit nor roll it back themselves.
+//
+// Example usage:
+//
+//	_, err := ctx.Tx().ExecContext(ctx.Context(), "UPDATE pets SET status = $1 WHERE id = $2", status, id)
+func (ctx *Request[T]) Tx() *sql.Tx {
+	return ctx.tx
+}
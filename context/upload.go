@@ -0,0 +1,309 @@
+package context
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultUploadMaxFileSize  = 10 << 20 // DefaultUploadMaxFileSize is the per-file limit used when UploadLimits.MaxFileSize is zero.
+	DefaultUploadMaxTotalSize = 32 << 20 // DefaultUploadMaxTotalSize is the request body limit used when UploadLimits.MaxTotalSize is zero.
+	DefaultUploadMaxFiles     = 10       // DefaultUploadMaxFiles is the file count limit used when UploadLimits.MaxFiles is zero.
+	DefaultUploadMaxFieldSize = 1 << 20  // DefaultUploadMaxFieldSize is the per-field limit used when UploadLimits.MaxFieldSize is zero.
+
+	sniffLen = 512
+)
+
+var (
+	ErrUploadTooLarge     = errors.New("upload exceeds the total size limit")
+	ErrFileTooLarge       = errors.New("file exceeds the size limit")
+	ErrFieldTooLarge      = errors.New("form field exceeds the size limit")
+	ErrTooManyFiles       = errors.New("upload exceeds the file count limit")
+	ErrFileTypeNotAllowed = errors.New("file type is not allowed")
+)
+
+// UploadLimits restricts a streaming upload. Zero values fall back to the package defaults.
+type UploadLimits struct {
+	MaxFileSize  int64    // MaxFileSize is the maximum size of a single file, in bytes.
+	MaxTotalSize int64    // MaxTotalSize is the maximum size of the whole request body, in bytes.
+	MaxFiles     int      // MaxFiles is the maximum number of files.
+	MaxFieldSize int64    // MaxFieldSize is the maximum size of a non-file form field, in bytes.
+	AllowedTypes []string // AllowedTypes lists the accepted MIME types, such as `image/png` or `image/*`. Empty accepts any type.
+}
+
+// UploadedFile describes a file received by Upload. ContentType is detected from the file content,
+// so it can be trusted, unlike DeclaredType, which is sent by the client.
+type UploadedFile struct {
+	Field        string `json:"field"`          // Field is the multipart form field name, empty for non-multipart bodies.
+	FileName     string `json:"fileName"`       // FileName is the base name sent by the client. Do not use it as a path.
+	DeclaredType string `json:"declaredType"`   // DeclaredType is the Content-Type declared by the client.
+	ContentType  string `json:"contentType"`    // ContentType is the MIME type sniffed from the first bytes of the file.
+	Size         int64  `json:"size"`           // Size is the number of bytes received. It is set once the file is fully read.
+	Checksum     string `json:"checksum"`       // Checksum is the hex encoded SHA-256 of the file. It is set once the file is fully read.
+	Path         string `json:"path,omitempty"` // Path is the location of the file on disk, when written by UploadToDir.
+}
+
+// UploadResult holds the files and the non-file form fields of an upload.
+type UploadResult struct {
+	Files  []UploadedFile
+	Values url.Values
+}
+
+// UploadHandler returns the destination of a file, once its content type is known.
+// Returning a nil writer skips the file. If the writer implements io.Closer, it is closed
+// once the file is fully written.
+type UploadHandler func(file *UploadedFile) (io.Writer, error)
+
+// Upload streams the files of the request body to the writers returned by handler, without
+// buffering the whole body in memory or in temporary files.
+//
+// Multipart bodies are read part by part. Any other body, such as application/octet-stream,
+// is handled as a single file. The content type of each file is detected from its first bytes
+// and checked against limits.AllowedTypes, and the SHA-256 checksum is computed while streaming.
+//
+// Parameters:
+//   - limits: The size, count and type limits of the upload.
+//   - handler: The function that returns the destination of each file.
+//
+// Returns:
+//   - *UploadResult: The received files and form values, also when an error stops the upload midway.
+//   - error: ErrUploadTooLarge, ErrFileTooLarge, ErrFieldTooLarge, ErrTooManyFiles or ErrFileTypeNotAllowed,
+//     wrapped with the offending file name, or the error of the handler or writer. When a file
+//     exceeds its limit, its writer has already received part of the content.
+//
+// Example usage:
+//
+//	result, err := ctx.Upload(goservectx.UploadLimits{AllowedTypes: []string{"image/*"}},
+//		func(file *goservectx.UploadedFile) (io.Writer, error) {
+//			return storage.Create(file.FileName)
+//		})
+//	if err != nil {
+//		ctx.Error(err.Error(), goservectx.UploadErrorStatus(err))
+//		return
+//	}
+func (ctx *Request[T]) Upload(limits UploadLimits, handler UploadHandler) (*UploadResult, error) {
+	limits = limits.withDefaults()
+	result := &UploadResult{Values: url.Values{}}
+
+	body := http.MaxBytesReader(*ctx.Writer, ctx.Request.Body, limits.MaxTotalSize)
+	ctx.Request.Body = body
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request.Header.Get(ContentType))
+	if mediaType != MultipartFormData {
+		file := UploadedFile{
+			FileName:     bodyFileName(ctx.Request.Header.Get("Content-Disposition")),
+			DeclaredType: ctx.Request.Header.Get(ContentType),
+		}
+		err := receiveFile(&file, body, limits, handler)
+		result.Files = append(result.Files, file)
+		return result, uploadError(err)
+	}
+
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		return result, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, uploadError(err)
+		}
+
+		if part.FileName() == "" {
+			value, err := readField(part, limits.MaxFieldSize)
+			_ = part.Close()
+			if err != nil {
+				return result, uploadError(err)
+			}
+
+			result.Values.Add(part.FormName(), value)
+			continue
+		}
+
+		if len(result.Files) >= limits.MaxFiles {
+			_ = part.Close()
+			return result, fmt.Errorf("%w: %d files", ErrTooManyFiles, limits.MaxFiles)
+		}
+
+		file := UploadedFile{
+			Field:        part.FormName(),
+			FileName:     part.FileName(),
+			DeclaredType: part.Header.Get(ContentType),
+		}
+		err = receiveFile(&file, part, limits, handler)
+		_ = part.Close()
+		result.Files = append(result.Files, file)
+
+		if err != nil {
+			return result, uploadError(err)
+		}
+	}
+}
+
+// UploadToDir streams the files of the request body to new files in dir, as Upload does.
+// Files are stored under generated names, never under the name sent by the client, and are
+// removed if the upload fails.
+//
+// Parameters:
+//   - limits: The size, count and type limits of the upload.
+//   - dir: The directory where the files are written.
+//
+// Returns:
+//   - *UploadResult: The received files, with their Path set, and form values.
+//   - error: The upload error, see Upload.
+func (ctx *Request[T]) UploadToDir(limits UploadLimits, dir string) (*UploadResult, error) {
+	result, err := ctx.Upload(limits, func(file *UploadedFile) (io.Writer, error) {
+		destination, err := os.CreateTemp(dir, "upload-*"+filepath.Ext(file.FileName))
+		if err != nil {
+			return nil, err
+		}
+
+		file.Path = destination.Name()
+		return destination, nil
+	})
+
+	if err != nil {
+		for _, file := range result.Files {
+			if file.Path != "" {
+				_ = os.Remove(file.Path)
+			}
+		}
+	}
+	return result, err
+}
+
+// UploadErrorStatus returns the HTTP status that matches an error returned by Upload:
+// 413 for size and count limits, 415 for rejected file types and 400 otherwise.
+func UploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUploadTooLarge),
+		errors.Is(err, ErrFileTooLarge),
+		errors.Is(err, ErrFieldTooLarge),
+		errors.Is(err, ErrTooManyFiles):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrFileTypeNotAllowed):
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+func (l UploadLimits) withDefaults() UploadLimits {
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = DefaultUploadMaxFileSize
+	}
+	if l.MaxTotalSize <= 0 {
+		l.MaxTotalSize = DefaultUploadMaxTotalSize
+	}
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = DefaultUploadMaxFiles
+	}
+	if l.MaxFieldSize <= 0 {
+		l.MaxFieldSize = DefaultUploadMaxFieldSize
+	}
+	return l
+}
+
+func (l UploadLimits) allows(contentType string) bool {
+	if len(l.AllowedTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, allowed := range l.AllowedTypes {
+		if strings.EqualFold(allowed, mediaType) {
+			return true
+		}
+
+		prefix, isWildcard := strings.CutSuffix(allowed, "/*")
+		if isWildcard && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// receiveFile sniffs the content type of the file, checks it and copies the content to the
+// handler writer, computing the size and checksum.
+func receiveFile(file *UploadedFile, reader io.Reader, limits UploadLimits, handler UploadHandler) error {
+	buffered := bufio.NewReaderSize(reader, sniffLen)
+	head, err := buffered.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return err
+	}
+
+	file.ContentType = http.DetectContentType(head)
+	if !limits.allows(file.ContentType) {
+		return fmt.Errorf("%w: %s is %s", ErrFileTypeNotAllowed, file.FileName, file.ContentType)
+	}
+
+	writer, err := handler(file)
+	if err != nil {
+		return err
+	}
+	if writer == nil {
+		writer = io.Discard
+	}
+
+	hash := sha256.New()
+	limited := io.LimitReader(buffered, limits.MaxFileSize+1)
+	size, err := io.Copy(io.MultiWriter(writer, hash), limited)
+	file.Size = size
+
+	if closer, ok := writer.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if size > limits.MaxFileSize {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrFileTooLarge, file.FileName, limits.MaxFileSize)
+	}
+
+	file.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+func readField(reader io.Reader, maxSize int64) (string, error) {
+	value, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(value)) > maxSize {
+		return "", fmt.Errorf("%w: %d bytes", ErrFieldTooLarge, maxSize)
+	}
+	return string(value), nil
+}
+
+func bodyFileName(contentDisposition string) string {
+	_, params, err := mime.ParseMediaType(contentDisposition)
+	if err != nil || params["filename"] == "" {
+		return ""
+	}
+	return filepath.Base(params["filename"])
+}
+
+// uploadError converts the error of the request body size limit into ErrUploadTooLarge.
+func uploadError(err error) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return fmt.Errorf("%w: %d bytes", ErrUploadTooLarge, maxBytesError.Limit)
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+package context
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func pngOf(size int) []byte {
+	content := make([]byte, len(pngHeader)+size)
+	copy(content, pngHeader)
+	return content
+}
+
+func newUploadContext(t *testing.T, files map[string][]byte, fields map[string]string) *Request[*mockPrincipal] {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set(ContentType, writer.FormDataContentType())
+	return Of[*mockPrincipal](httptest.NewRecorder(), req, "testReference")
+}
+
+func TestRequest_Upload(t *testing.T) {
+	t.Run("should stream files and compute the checksum", func(t *testing.T) {
+		content := pngOf(1024)
+		ctx := newUploadContext(t, map[string][]byte{"pet.png": content}, map[string]string{"name": "doggie"})
+
+		var received bytes.Buffer
+		result, err := ctx.Upload(UploadLimits{AllowedTypes: []string{"image/*"}}, func(file *UploadedFile) (io.Writer, error) {
+			assert.Equal(t, "image/png", file.ContentType)
+			return &received, nil
+		})
+		require.NoError(t, err)
+
+		checksum := sha256.Sum256(content)
+		require.Len(t, result.Files, 1)
+		assert.Equal(t, "pet.png", result.Files[0].FileName)
+		assert.Equal(t, int64(len(content)), result.Files[0].Size)
+		assert.Equal(t, hex.EncodeToString(checksum[:]), result.Files[0].Checksum)
+		assert.Equal(t, content, received.Bytes())
+		assert.Equal(t, "doggie", result.Values.Get("name"))
+	})
+
+	t.Run("should reject files by their sniffed type", func(t *testing.T) {
+		ctx := newUploadContext(t, map[string][]byte{"pet.png": []byte("#!/bin/sh\necho not an image")}, nil)
+
+		_, err := ctx.Upload(UploadLimits{AllowedTypes: []string{"image/*"}}, func(*UploadedFile) (io.Writer, error) {
+			t.Fatal("handler must not be called for a rejected file")
+			return nil, nil
+		})
+		require.ErrorIs(t, err, ErrFileTypeNotAllowed)
+		assert.Equal(t, http.StatusUnsupportedMediaType, UploadErrorStatus(err))
+	})
+
+	t.Run("should enforce the file size limit", func(t *testing.T) {
+		ctx := newUploadContext(t, map[string][]byte{"pet.png": pngOf(100)}, nil)
+
+		_, err := ctx.Upload(UploadLimits{MaxFileSize: 64}, func(*UploadedFile) (io.Writer, error) {
+			return io.Discard, nil
+		})
+		require.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, UploadErrorStatus(err))
+	})
+
+	t.Run("should enforce the total size limit", func(t *testing.T) {
+		ctx := newUploadContext(t, map[string][]byte{"pet.png": make([]byte, 4096)}, nil)
+
+		_, err := ctx.Upload(UploadLimits{MaxTotalSize: 1024}, func(*UploadedFile) (io.Writer, error) {
+			return io.Discard, nil
+		})
+		require.ErrorIs(t, err, ErrUploadTooLarge)
+	})
+
+	t.Run("should enforce the file count limit", func(t *testing.T) {
+		ctx := newUploadContext(t, map[string][]byte{"a.png": pngHeader, "b.png": pngHeader}, nil)
+
+		_, err := ctx.Upload(UploadLimits{MaxFiles: 1}, func(*UploadedFile) (io.Writer, error) {
+			return io.Discard, nil
+		})
+		require.ErrorIs(t, err, ErrTooManyFiles)
+	})
+
+	t.Run("should handle a raw body as a single file", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(pngHeader))
+		req.Header.Set(ContentType, "application/octet-stream")
+		ctx := Of[*mockPrincipal](httptest.NewRecorder(), req, "testReference")
+
+		result, err := ctx.UploadToDir(UploadLimits{}, t.TempDir())
+		require.NoError(t, err)
+		require.Len(t, result.Files, 1)
+		assert.Equal(t, "image/png", result.Files[0].ContentType)
+
+		stored, err := os.ReadFile(result.Files[0].Path)
+		require.NoError(t, err)
+		assert.Equal(t, pngHeader, stored)
+	})
+
+	t.Run("should remove the stored files when the upload fails", func(t *testing.T) {
+		dir := t.TempDir()
+		ctx := newUploadContext(t, map[string][]byte{"pet.png": pngOf(100)}, nil)
+
+		_, err := ctx.UploadToDir(UploadLimits{MaxFileSize: 64}, dir)
+		require.ErrorIs(t, err, ErrFileTooLarge)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
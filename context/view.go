@@ -0,0 +1,69 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/view"
+)
+
+// ErrNoViews is returned by HTML when the server has no template engine.
+var ErrNoViews = errors.New("no template engine configured")
+
+// UseViews sets the template engine used by HTML. It is usually configured on the server with
+// Api.Views, instead of being called by handlers.
+func (ctx *Request[T]) UseViews(engine *view.Engine) {
+	ctx.views = engine
+}
+
+// HTML renders the template with the given name as an HTML response. The page is rendered before
+// anything is written, so a template error is answered as a 500 problem instead of a partial page.
+// The templates can call principal, csrfToken, csrfField, locale and t, which translates a
+// message key into the request Locale.
+//
+// Parameters:
+//   - status: The HTTP status code.
+//   - name: The template name, its path without the extension, such as users/list.
+//   - data: The data of the template.
+//
+// Returns:
+//   - error: nil on success, or the error answered as a 500 problem.
+//
+// Example usage:
+//
+//	ctx.HTML(http.StatusOK, "users/list", map[string]any{"Users": users})
+func (ctx *Request[T]) HTML(status int, name string, data any) error {
+	if ctx.views == nil {
+		log.Errorf("[%s]:: HTML: no template engine, configure one with Api.Views", ctx.GetSessionId())
+		ctx.InternalServerError("Failed to render the page")
+		return ErrNoViews
+	}
+
+	var principal any
+	if ctx.Principal != nil {
+		principal = *ctx.Principal
+	}
+
+	var buffer bytes.Buffer
+	err := ctx.views.Render(&buffer, name, data, view.Request{
+		Principal: principal,
+		CsrfToken: ctx.CsrfToken(),
+		CsrfField: GetOrElse(ctx, CsrfFieldKey, view.DefaultCsrfField),
+		Locale:    ctx.Locale(),
+		Translate: ctx.Translate,
+	})
+	if err != nil {
+		log.Errorf("[%s]:: HTML: failed to render %s: %v", ctx.GetSessionId(), name, err)
+		ctx.InternalServerError("Failed to render the page")
+		return err
+	}
+
+	writer := *ctx.Writer
+	writer.Header().Set(ContentType, view.ContentType)
+	writer.WriteHeader(status)
+	_, err = writer.Write(buffer.Bytes())
+	ctx.Done()
+	return err
+}
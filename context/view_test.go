@@ -0,0 +1,47 @@
+package context
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/view"
+)
+
+func TestRequest_HTML(t *testing.T) {
+	engine, err := view.New(fstest.MapFS{
+		"page.html":   {Data: []byte(`<p>{{.}} {{csrfToken}}</p>`)},
+		"broken.html": {Data: []byte(`{{.Missing.Field}}`)},
+	})
+	require.NoError(t, err)
+
+	t.Run("should render the template as HTML", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseViews(engine)
+		Set(ctx, CsrfTokenKey, "token")
+
+		require.NoError(t, ctx.HTML(http.StatusCreated, "page", "<hello>"))
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Equal(t, view.ContentType, rr.Header().Get(ContentType))
+		require.Equal(t, `<p>&lt;hello&gt; token</p>`, rr.Body.String())
+		require.True(t, ctx.Completed)
+	})
+
+	t.Run("should answer a server error when the template fails", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+		ctx.UseViews(engine)
+
+		require.Error(t, ctx.HTML(http.StatusOK, "broken", 42))
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.NotContains(t, rr.Body.String(), "<p>")
+	})
+
+	t.Run("should answer a server error without template engine", func(t *testing.T) {
+		ctx, rr := etagRequest(http.MethodGet, nil)
+
+		require.ErrorIs(t, ctx.HTML(http.StatusOK, "page", nil), ErrNoViews)
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/problem"
 )
 
 const (
@@ -21,13 +22,19 @@ func Handler(try func(), catch func(err error)) {
 	defer func() {
 		if r := recover(); r != nil {
 			_, file, line, ok := runtime.Caller(2) // Adjust caller depth to log where the error originates
-			var errMessage = fmt.Sprintf("panic occurred at %s:%d - %v", file, line, r)
+			location := "panic occurred"
 
-			if !ok {
-				errMessage = fmt.Sprintf("panic occurred - %v", r)
+			if ok {
+				location = fmt.Sprintf("panic occurred at %s:%d", file, line)
 			}
 
-			catch(Wrapper(errors.New(errMessage), "Recovered panic"))
+			// Keep panicked errors, such as typed problems, reachable through errors.As.
+			if panicErr, isErr := r.(error); isErr {
+				catch(Wrapper(fmt.Errorf("%s - %w", location, panicErr), "Recovered panic"))
+				return
+			}
+
+			catch(Wrapper(errors.New(fmt.Sprintf("%s - %v", location, r)), "Recovered panic"))
 		}
 	}()
 	try()
@@ -57,6 +64,12 @@ func Default[T goservectx.Principal]() ApiHandler[T] {
 
 func (p *defaultHandlerImpl[T]) Handler(ctx *goservectx.Request[T], err error, source string) {
 	log.Errorf("%s failed with error: %+v", source, err)
+
+	if problem.Is(err) {
+		ctx.Problem(err)
+		return
+	}
+
 	if source == HandlerWrapper {
 		ctx.InternalServerError("Internal server error")
 	}
@@ -0,0 +1,297 @@
+// Package fieldset implements sparse fieldsets: the `?fields=` and `?exclude=` query parameters
+// selecting the members of a JSON response, by nested path such as owner.name.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+const (
+	// FieldsParam is the query parameter listing the members to keep, such as ?fields=id,owner.name.
+	FieldsParam = "fields"
+
+	// ExcludeParam is the query parameter listing the members to drop, such as ?exclude=owner.address.
+	ExcludeParam = "exclude"
+)
+
+// node is a level of a selection tree. A node without children selects the whole member.
+type node map[string]node
+
+func (n node) add(path []string) {
+	child, ok := n[path[0]]
+	if len(path) == 1 {
+		// The whole member wins over any of its children.
+		n[path[0]] = node{}
+		return
+	}
+
+	if ok && len(child) == 0 {
+		return
+	}
+	if !ok {
+		child = node{}
+		n[path[0]] = child
+	}
+	child.add(path[1:])
+}
+
+// Selection holds the members selected by the fields and exclude parameters. Paths follow the
+// JSON names of the members, as written by the json tags, and go through arrays, so items.name
+// selects the name of every item. A Selection is immutable and safe for concurrent use.
+type Selection struct {
+	fields  node
+	exclude node
+}
+
+// Error reports a fields or exclude parameter selecting a member that is not selectable.
+type Error struct {
+	Param string // Param is the query parameter, fields or exclude.
+	Path  string // Path is the member path that is not selectable.
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s parameter selects %q, which is not selectable", e.Param, e.Path)
+}
+
+// Problem answers the error as a 400 Bad Request problem.
+func (e *Error) Problem() *problem.Problem {
+	return problem.New(http.StatusBadRequest, "invalid-fieldset", e.Error()).
+		With("param", e.Param).
+		With("path", e.Path)
+}
+
+// Parse builds the Selection of comma separated paths of members to keep and to drop.
+//
+// Parameters:
+//   - fields: The paths of the members to keep, such as `id,name,owner.name`. Empty keeps every member.
+//   - exclude: The paths of the members to drop, such as `owner.address`, applied after fields.
+//
+// Returns:
+//   - *Selection: The selection, or nil when both lists are empty.
+func Parse(fields string, exclude string) *Selection {
+	selection := &Selection{fields: parsePaths(fields), exclude: parsePaths(exclude)}
+	if selection.IsEmpty() {
+		return nil
+	}
+	return selection
+}
+
+// FromQuery builds the Selection of the fields and exclude query parameters, rejecting the paths
+// that are not selectable. Repeated parameters are joined.
+//
+// Parameters:
+//   - query: The query parameters of the request.
+//   - selectable: The paths that can be selected, such as `id`, `name` or `owner`, which makes its
+//     children selectable too. When empty, every path is selectable.
+//
+// Returns:
+//   - *Selection: The selection, or nil when the request selects no member.
+//   - error: An *Error when a selected path is not selectable.
+//
+// Example usage:
+//
+//	selection, err := fieldset.FromQuery(r.URL.Query(), []string{"id", "name", "owner"})
+func FromQuery(query url.Values, selectable []string) (*Selection, error) {
+	selection := Parse(strings.Join(query[FieldsParam], ","), strings.Join(query[ExcludeParam], ","))
+	if selection == nil || len(selectable) == 0 {
+		return selection, nil
+	}
+
+	allowed := make([][]string, 0, len(selectable))
+	for _, path := range selectable {
+		allowed = append(allowed, strings.Split(path, "."))
+	}
+
+	for _, param := range []string{FieldsParam, ExcludeParam} {
+		tree := selection.fields
+		if param == ExcludeParam {
+			tree = selection.exclude
+		}
+
+		paths := tree.paths(nil)
+		slices.SortFunc(paths, func(a []string, b []string) int {
+			return slices.Compare(a, b)
+		})
+
+		for _, path := range paths {
+			if !slices.ContainsFunc(allowed, func(prefix []string) bool {
+				return len(prefix) <= len(path) && slices.Equal(prefix, path[:len(prefix)])
+			}) {
+				return nil, &Error{Param: param, Path: strings.Join(path, ".")}
+			}
+		}
+	}
+	return selection, nil
+}
+
+func parsePaths(list string) node {
+	var tree node
+	for _, path := range strings.Split(list, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		tokens := strings.Split(path, ".")
+		if slices.Contains(tokens, "") {
+			continue
+		}
+
+		if tree == nil {
+			tree = node{}
+		}
+		tree.add(tokens)
+	}
+	return tree
+}
+
+// paths lists the selected leaves of the tree.
+func (n node) paths(prefix []string) [][]string {
+	var paths [][]string
+	for name, child := range n {
+		path := append(append([]string{}, prefix...), name)
+		if len(child) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, child.paths(path)...)
+	}
+	return paths
+}
+
+// IsEmpty reports whether the selection keeps every member.
+func (s *Selection) IsEmpty() bool {
+	return s == nil || (len(s.fields) == 0 && len(s.exclude) == 0)
+}
+
+// Filter returns the JSON document with the selected members only, keeping their order.
+// Arrays are filtered item by item, and scalar values are returned unchanged.
+//
+// Parameters:
+//   - document: The JSON document, usually an encoded response body.
+//
+// Returns:
+//   - []byte: The filtered JSON document.
+//   - error: An error if the document is not valid JSON.
+func (s *Selection) Filter(document []byte) ([]byte, error) {
+	if s.IsEmpty() {
+		return document, nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.Grow(len(document))
+
+	if err := filter(&buffer, bytes.TrimSpace(document), s.fields, false); err != nil {
+		return nil, err
+	}
+
+	if len(s.exclude) == 0 {
+		return buffer.Bytes(), nil
+	}
+
+	filtered := buffer.Bytes()
+	var excluded bytes.Buffer
+	excluded.Grow(len(filtered))
+	if err := filter(&excluded, filtered, s.exclude, true); err != nil {
+		return nil, err
+	}
+	return excluded.Bytes(), nil
+}
+
+// Marshal encodes the value as JSON, following its json tags, and filters it as Filter does.
+// It works on structs, maps, slices and any other value encoding/json accepts.
+func (s *Selection) Marshal(value any) ([]byte, error) {
+	document, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return s.Filter(document)
+}
+
+// filter writes the document keeping the members of the tree, or dropping them when exclude is set.
+func filter(buffer *bytes.Buffer, document []byte, tree node, exclude bool) error {
+	if len(tree) == 0 || len(document) == 0 {
+		buffer.Write(document)
+		return nil
+	}
+
+	switch document[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(document, &items); err != nil {
+			return err
+		}
+
+		buffer.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := filter(buffer, item, tree, exclude); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+		return nil
+
+	case '{':
+		return filterObject(buffer, document, tree, exclude)
+
+	default:
+		buffer.Write(document)
+		return nil
+	}
+}
+
+func filterObject(buffer *bytes.Buffer, document []byte, tree node, exclude bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	buffer.WriteByte('{')
+	written := false
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		child, selected := tree[key]
+		if exclude && selected && len(child) == 0 {
+			continue
+		}
+		if !exclude && !selected {
+			continue
+		}
+
+		if written {
+			buffer.WriteByte(',')
+		}
+		written = true
+
+		encodedKey, _ := json.Marshal(key)
+		buffer.Write(encodedKey)
+		buffer.WriteByte(':')
+		if err := filter(buffer, value, child, exclude); err != nil {
+			return err
+		}
+	}
+	buffer.WriteByte('}')
+	return nil
+}
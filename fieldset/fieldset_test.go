@@ -0,0 +1,130 @@
+package fieldset
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+type owner struct {
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Address string `json:"address"`
+}
+
+type pet struct {
+	Id     int               `json:"id"`
+	Name   string            `json:"petName"`
+	Tags   []string          `json:"tags"`
+	Owner  *owner            `json:"owner"`
+	Labels map[string]string `json:"labels"`
+	Secret string            `json:"-"`
+}
+
+var doggie = pet{
+	Id:     1,
+	Name:   "doggie",
+	Tags:   []string{"dog"},
+	Owner:  &owner{Name: "Ana", Address: "Main"},
+	Labels: map[string]string{"color": "brown", "size": "small"},
+}
+
+func TestSelection_Marshal(t *testing.T) {
+	t.Run("should keep the selected members following the json tags", func(t *testing.T) {
+		document, err := Parse("id,petName,owner.name", "").Marshal(doggie)
+
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1,"petName":"doggie","owner":{"name":"Ana"}}`, string(document))
+	})
+
+	t.Run("should drop the excluded members", func(t *testing.T) {
+		document, err := Parse("", "tags,owner.address,labels.size").Marshal(doggie)
+
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1,"petName":"doggie","owner":{"name":"Ana"},"labels":{"color":"brown"}}`, string(document))
+	})
+
+	t.Run("should exclude after selecting", func(t *testing.T) {
+		document, err := Parse("id,owner", "owner.address").Marshal(doggie)
+
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1,"owner":{"name":"Ana"}}`, string(document))
+	})
+
+	t.Run("should filter every item of slices", func(t *testing.T) {
+		document, err := Parse("items.id", "").Marshal(map[string]any{
+			"items": []pet{doggie, {Id: 2}},
+			"total": 2,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, `{"items":[{"id":1},{"id":2}]}`, string(document))
+
+		document, err = Parse("petName", "").Marshal([]pet{doggie})
+		require.NoError(t, err)
+		require.Equal(t, `[{"petName":"doggie"}]`, string(document))
+	})
+
+	t.Run("should keep the whole member when selected with one of its children", func(t *testing.T) {
+		document, err := Parse("owner.name,owner", "").Marshal(doggie)
+
+		require.NoError(t, err)
+		require.Equal(t, `{"owner":{"name":"Ana","address":"Main"}}`, string(document))
+	})
+
+	t.Run("should leave null and scalar values unchanged", func(t *testing.T) {
+		document, err := Parse("owner.name", "").Marshal(pet{})
+		require.NoError(t, err)
+		require.Equal(t, `{"owner":null}`, string(document))
+
+		document, err = Parse("id", "").Marshal("doggie")
+		require.NoError(t, err)
+		require.Equal(t, `"doggie"`, string(document))
+	})
+
+	t.Run("should ignore empty selections", func(t *testing.T) {
+		require.Nil(t, Parse(" , ", ".."))
+		require.True(t, Parse("", "").IsEmpty())
+	})
+}
+
+func TestFromQuery(t *testing.T) {
+	query := url.Values{FieldsParam: {"id,owner.name", "petName"}, ExcludeParam: {"owner.email"}}
+
+	t.Run("should accept the selectable paths and their children", func(t *testing.T) {
+		selection, err := FromQuery(query, []string{"id", "petName", "owner"})
+
+		require.NoError(t, err)
+		document, err := selection.Marshal(doggie)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1,"petName":"doggie","owner":{"name":"Ana"}}`, string(document))
+	})
+
+	t.Run("should reject paths that are not selectable", func(t *testing.T) {
+		_, err := FromQuery(query, []string{"id", "petName", "owner.name"})
+
+		require.Error(t, err)
+		p := problem.From(err)
+		require.Equal(t, http.StatusBadRequest, p.Status)
+		require.Equal(t, ExcludeParam, p.Extensions["param"])
+		require.Equal(t, "owner.email", p.Extensions["path"])
+	})
+
+	t.Run("should select every path without selectable paths", func(t *testing.T) {
+		selection, err := FromQuery(query, nil)
+
+		require.NoError(t, err)
+		require.False(t, selection.IsEmpty())
+	})
+
+	t.Run("should answer nil without parameters", func(t *testing.T) {
+		selection, err := FromQuery(url.Values{}, []string{"id"})
+
+		require.NoError(t, err)
+		require.Nil(t, selection)
+	})
+}
@@ -1,14 +1,18 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/i18n"
+	"github.com/softwareplace/goserve/problem"
 	goservereflect "github.com/softwareplace/goserve/reflect"
 	"github.com/softwareplace/goserve/validator"
 )
@@ -22,10 +26,11 @@ type FieldSource string
 
 // RequestError represents a validation error with contextual information
 type RequestError struct {
-	Field   string      `json:"field"`      // The original field name from request
-	Source  FieldSource `json:"source"`     // Where the field came from
-	Message string      `json:"message"`    // Human-readable error message
-	Code    int         `json:"statusCode"` // HTTP status code
+	Field   string                 `json:"field"`            // The original field name from request
+	Source  FieldSource            `json:"source"`           // Where the field came from
+	Message string                 `json:"message"`          // Human-readable error message
+	Code    int                    `json:"statusCode"`       // HTTP status code
+	Errors  []validator.FieldError `json:"errors,omitempty"` // Every failed field, with its JSON path, tag, param, source and message
 }
 
 // Error implements the error interface
@@ -33,6 +38,14 @@ func (e *RequestError) Error() string {
 	return fmt.Sprintf("%s %s", e.Source, e.Message)
 }
 
+// Problem renders the request error as a problem whose errors member lists every failed field,
+// so that clients can highlight them.
+func (e *RequestError) Problem() *problem.Problem {
+	return problem.New(e.Code, "validation", e.Message).
+		With("errors", e.Errors).
+		Wrap(e)
+}
+
 // FormValues returns the form values of the request
 func FormValues(r *http.Request) url.Values {
 	if r.Form == nil {
@@ -46,7 +59,13 @@ func FormValues(r *http.Request) url.Values {
 
 // BindRequestParams extracts and binds request parameters such as query, form data,
 // headers, or route vars into a target struct.
-// It validates the target struct and returns a RequestError with details on validation failure, or nil on success.
+// Structs declaring `query`, `header`, `path`, `cookie` or `form` tags are bound by those tags only,
+// with `default` values and typed conversions, see reflect.Bind. Parameters that cannot be converted
+// are answered with a RequestError listing them with their source, before any validation.
+// Their untagged fields, and every field of other structs, keep matching parameters by
+// case-insensitive name or json tag.
+// It validates the target struct, with the messages of the locale selected from the Accept-Language
+// header, and returns a RequestError with details on validation failure, or nil on success.
 func BindRequestParams(r *http.Request, target interface{}) *RequestError {
 	contentType := r.Header.Get(context.ContentType)
 
@@ -57,25 +76,123 @@ func BindRequestParams(r *http.Request, target interface{}) *RequestError {
 			})
 	}
 
-	_ = goservereflect.ParamsExtract(target,
-		goservereflect.ParamsExtractorSource{
+	if goservereflect.HasBindingTags(reflect.TypeOf(target)) {
+		// Fields without a binding tag keep matching parameters by name.
+		untaggedErr := goservereflect.ParamsExtractUntagged(target, paramsSources(r)...)
+
+		err := goservereflect.Bind(target, goservereflect.Sources{
+			Query:   r.URL.Query(),
+			Header:  r.Header,
+			Path:    mux.Vars(r),
+			Cookies: r.Cookies(),
+			Form:    formValuesOf(r, target, contentType),
+		})
+
+		if err = joinBindErrors(untaggedErr, err); err != nil {
+			return bindRequestError(err)
+		}
+	} else if err := goservereflect.ParamsExtract(target, paramsSources(r)...); err != nil {
+		return bindRequestError(err)
+	}
+
+	locale := i18n.Match(r.Header.Get("Accept-Language"))
+	err := validator.Shared().StructLocale(target, locale)
+
+	if err != nil {
+		requestError := &RequestError{
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+
+		var validationErrors *validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			requestError.Errors = validationErrors.Fields
+			if len(validationErrors.Fields) == 1 {
+				requestError.Field = validationErrors.Fields[0].Field
+				requestError.Source = FieldSource(validationErrors.Fields[0].Source)
+			}
+		}
+		return requestError
+	}
+	return nil
+}
+
+func paramsSources(r *http.Request) []goservereflect.ParamsExtractorSource {
+	return []goservereflect.ParamsExtractorSource{
+		{
+			Name: goservereflect.SourceQuery,
 			Tree: r.URL.Query(),
 		},
-		goservereflect.ParamsExtractorSource{
+		{
+			Name: goservereflect.SourceHeader,
 			Tree: r.Header,
 		},
-		goservereflect.ParamsExtractorSource{
+		{
+			Name:   goservereflect.SourcePath,
 			Source: mux.Vars(r),
 		},
-	)
+	}
+}
 
-	err := validator.StructValidation(target)
+// formValuesOf returns the form values of the request, parsing the body only when the target
+// declares a `form` tag.
+func formValuesOf(r *http.Request, target interface{}, contentType string) url.Values {
+	if !goservereflect.HasBindingSource(reflect.TypeOf(target), goservereflect.SourceForm) {
+		return nil
+	}
 
-	if err != nil {
-		return &RequestError{
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
+	if strings.Contains(contentType, context.MultipartFormData) {
+		return FormValues(r)
+	}
+
+	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err == nil {
+			return r.PostForm
 		}
 	}
 	return nil
 }
+
+// joinBindErrors merges the BindErrors of the untagged and the tagged fields into one error.
+func joinBindErrors(errs ...error) error {
+	var joined goservereflect.BindErrors
+	for _, err := range errs {
+		var bindErrors goservereflect.BindErrors
+		if errors.As(err, &bindErrors) {
+			joined = append(joined, bindErrors...)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if len(joined) > 0 {
+		return joined
+	}
+	return nil
+}
+
+func bindRequestError(err error) *RequestError {
+	requestError := &RequestError{
+		Message: err.Error(),
+		Code:    http.StatusBadRequest,
+	}
+
+	var bindErrors goservereflect.BindErrors
+	if errors.As(err, &bindErrors) {
+		for _, bindError := range bindErrors {
+			requestError.Errors = append(requestError.Errors, validator.FieldError{
+				Field:   bindError.Field,
+				Tag:     "type",
+				Param:   bindError.Type,
+				Source:  bindError.Source,
+				Message: bindError.Error(),
+			})
+		}
+
+		if len(bindErrors) == 1 {
+			requestError.Field = bindErrors[0].Field
+			requestError.Source = FieldSource(bindErrors[0].Source)
+		}
+	}
+	return requestError
+}
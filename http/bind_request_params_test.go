@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
@@ -103,6 +104,122 @@ func TestRequest_BindRequestParams(t *testing.T) {
 		err = json.Unmarshal(recorder.Body.Bytes(), &bodyData)
 		require.NoError(t, err)
 
-		require.Equal(t, expected, bodyData["message"])
+		require.Equal(t, expected, bodyData["detail"])
+		require.Len(t, errBind.Errors, 4)
+		require.Equal(t, "X-Api-Key", errBind.Errors[0].Field)
+		require.Equal(t, "header", errBind.Errors[0].Source)
+		require.Equal(t, "userId", errBind.Errors[3].Field)
+		require.Equal(t, "path", errBind.Errors[3].Source)
+	})
+
+	t.Run("should render the failed fields as an array", func(t *testing.T) {
+		router := mux.NewRouter()
+		router.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.Of[*context.DefaultContext](w, r, "test")
+			request := MockRequest{}
+			if errBind := BindRequestParams(r, &request); errBind != nil {
+				ctx.Problem(errBind)
+				return
+			}
+			ctx.Ok(request)
+		}).Methods("POST")
+
+		req, err := http.NewRequest("POST", "/login?page=1&count=1", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Api-Key", "test")
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.JSONEq(t, `[{
+			"field": "userId",
+			"tag": "required",
+			"source": "path",
+			"message": "UserId is a required field"
+		}]`, membersOf(t, recorder.Body.Bytes(), "errors"))
+	})
+}
+
+func membersOf(t *testing.T, body []byte, member string) string {
+	var bodyData map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &bodyData))
+	return string(bodyData[member])
+}
+
+type listRequest struct {
+	Page    int           `query:"page" default:"1" validate:"gte=1"`
+	Id      string        `query:"id"`
+	TraceId string        `header:"id"`
+	Timeout time.Duration `header:"X-Timeout"`
+}
+
+func TestRequest_BindRequestParams_Tags(t *testing.T) {
+	t.Run("should bind each field from its own source with defaults", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pets?id=query-id", nil)
+		req.Header.Set("id", "header-id")
+		req.Header.Set("X-Timeout", "2s")
+
+		var request listRequest
+		require.Nil(t, BindRequestParams(req, &request))
+		require.Equal(t, listRequest{Page: 1, Id: "query-id", TraceId: "header-id", Timeout: 2 * time.Second}, request)
+	})
+
+	t.Run("should report conversion failures with their source", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pets?page=first", nil)
+
+		var request listRequest
+		errBind := BindRequestParams(req, &request)
+		require.NotNil(t, errBind)
+		require.Equal(t, http.StatusBadRequest, errBind.Code)
+		require.Equal(t, "page", errBind.Field)
+		require.Equal(t, FieldSource("query"), errBind.Source)
+		require.Equal(t, "int", errBind.Errors[0].Param)
+	})
+}
+
+// generatedHeaderRequest is tagged as the gorilla-interface template tags the header parameters.
+type generatedHeaderRequest struct {
+	XRequestId *string `name:"X-Request-Id" header:"X-Request-Id" json:"X-Request-Id"`
+	Limit      *int    `name:"limit" query:"limit" json:"limit"`
+}
+
+type untaggedRequest struct {
+	Page int    `json:"page"`
+	Name string `json:"name"`
+}
+
+func TestRequest_BindRequestParams_Sources(t *testing.T) {
+	t.Run("should bind the header parameters of generated requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pets?limit=5", nil)
+		req.Header.Set("X-Request-Id", "request-42")
+
+		var request generatedHeaderRequest
+		require.Nil(t, BindRequestParams(req, &request))
+		require.NotNil(t, request.XRequestId)
+		require.Equal(t, "request-42", *request.XRequestId)
+		require.Equal(t, 5, *request.Limit)
+	})
+
+	t.Run("should report conversion failures of untagged requests with their source", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pets?page=first&name=Rex", nil)
+
+		var request untaggedRequest
+		errBind := BindRequestParams(req, &request)
+		require.NotNil(t, errBind)
+		require.Equal(t, http.StatusBadRequest, errBind.Code)
+		require.Equal(t, "page", errBind.Field)
+		require.Equal(t, FieldSource("query"), errBind.Source)
+		require.Equal(t, "int", errBind.Errors[0].Param)
+		require.Equal(t, "Rex", request.Name)
+	})
+
+	t.Run("should leave urlencoded bodies unread without form fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/pets?limit=5", strings.NewReader("name=Rex"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var request generatedHeaderRequest
+		require.Nil(t, BindRequestParams(req, &request))
+		require.Nil(t, req.PostForm)
 	})
 }
@@ -3,24 +3,34 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"reflect"
 	"strings"
 
 	goservectx "github.com/softwareplace/goserve/context"
 	goserveerror "github.com/softwareplace/goserve/error"
+	"github.com/softwareplace/goserve/problem"
+	"github.com/softwareplace/goserve/validator"
 )
 
 type OnSuccess[B any, T goservectx.Principal] func(ctx *goservectx.Request[T], body B)
 type OnError[T goservectx.Principal] func(ctx *goservectx.Request[T], err error)
 
-func FailedToLoadBody[T goservectx.Principal](ctx *goservectx.Request[T], _ error) {
+// FailedToLoadBody answers validation errors with the failed fields, translated into the request
+// locale, and any other error with a generic "Invalid request data" bad request.
+func FailedToLoadBody[T goservectx.Principal](ctx *goservectx.Request[T], err error) {
+	if problem.Is(err) {
+		ctx.Problem(err)
+		return
+	}
 	ctx.Error("Invalid request data", http.StatusBadRequest)
 }
 
 // GetRequestBody parses the JSON request body and executes the appropriate success or error handler.
 // ctx is the request context containing headers and the request body.
-// target is the variable to decode the request body into.
+// target is the variable to decode the request body into. Struct targets are validated using their
+// validate tags, with the messages of the locale selected from the Accept-Language header.
 // onSuccess is invoked if the request body is successfully parsed or if Content-Type is unsupported.
-// onError is invoked if JSON decoding fails or any other error occurs.
+// onError is invoked if JSON decoding or validation fails or any other error occurs.
 func GetRequestBody[B any, T goservectx.Principal](
 	ctx *goservectx.Request[T],
 	target B,
@@ -37,6 +47,13 @@ func GetRequestBody[B any, T goservectx.Principal](
 				onError(ctx, err)
 				return
 			}
+
+			if isStruct(target) {
+				if err := validator.Shared().StructLocale(target, ctx.Locale()); err != nil {
+					onError(ctx, err)
+					return
+				}
+			}
 			onSuccess(ctx, target)
 			return
 		}
@@ -46,3 +63,14 @@ func GetRequestBody[B any, T goservectx.Principal](
 		onError(ctx, err)
 	})
 }
+
+func isStruct(target any) bool {
+	value := reflect.ValueOf(target)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	return value.Kind() == reflect.Struct
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/context"
+)
+
+type petRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestGetRequestBody(t *testing.T) {
+	serve := func(body string, acceptLanguage string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(body))
+		req.Header.Set("Accept-Language", acceptLanguage)
+
+		ctx := context.Of[*context.DefaultContext](recorder, req, "test")
+		GetRequestBody(ctx, petRequest{}, func(ctx *context.Request[*context.DefaultContext], body petRequest) {
+			ctx.Ok(body)
+		}, FailedToLoadBody[*context.DefaultContext])
+		return recorder
+	}
+
+	t.Run("should answer the failed fields in the request locale", func(t *testing.T) {
+		recorder := serve(`{}`, "pt-BR")
+
+		var body struct {
+			Errors []map[string]string `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+		require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		require.Equal(t, "name", body.Errors[0]["field"])
+		require.Equal(t, "Name é um campo obrigatório", body.Errors[0]["message"])
+	})
+
+	t.Run("should translate the invalid request data message", func(t *testing.T) {
+		recorder := serve(`{`, "de-DE")
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "Ungültige Anfragedaten")
+	})
+
+	t.Run("should accept a valid body", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, serve(`{"name": "Rex"}`, "").Code)
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,7 +31,12 @@ func (i *_impl) build(method string, config *Config) (*http.Request, error) {
 		requestHost += "/" + requestPath
 	}
 
-	req, err := http.NewRequest(method, requestHost, body)
+	ctx := config.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestHost, body)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create POST request: %v", err)
@@ -66,5 +72,5 @@ func (i *_impl) Exec(method string, config *Config) (*http.Response, error) {
 	resp, err := client.Do(request)
 
 	i.response = resp
-	return resp, nil
+	return resp, err
 }
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/patch"
+)
+
+// PatchRequestBody reads the `application/json-patch+json` or `application/merge-patch+json`
+// request body and applies it to target with the patcher, validating the result with the messages
+// of the locale selected from the Accept-Language header. The target is only changed on success.
+// An unsupported content type sets the Accept-Patch header, listing the supported ones.
+//
+// Parameters:
+//   - ctx: The request context holding the patch document.
+//   - patcher: The patcher, restricting the paths that can be changed.
+//   - target: A pointer to the current resource.
+//
+// Returns:
+//   - error: nil on success, or an error answered as a problem by ctx.Problem.
+//
+// Example usage:
+//
+//	var petPatcher = patch.New("/name", "/status")
+//
+//	func (s *Service) PatchPet(ctx *goservectx.Request[*Principal]) {
+//		pet := s.repository.Find(ctx.PathValueOf("id"))
+//		if err := goservehttp.PatchRequestBody(ctx, petPatcher, &pet); err != nil {
+//			ctx.Problem(err)
+//			return
+//		}
+//		ctx.Ok(s.repository.Save(pet))
+//	}
+func PatchRequestBody[T goservectx.Principal](ctx *goservectx.Request[T], patcher *patch.Patcher, target any) error {
+	document, err := io.ReadAll(io.LimitReader(ctx.Request.Body, patch.MaxDocumentSize+1))
+	if err != nil {
+		return &patch.Error{Reason: err.Error(), Err: patch.ErrInvalidDocument}
+	}
+
+	if len(document) > patch.MaxDocumentSize {
+		return &patch.Error{
+			Reason: fmt.Sprintf("document larger than %d bytes", patch.MaxDocumentSize),
+			Err:    patch.ErrInvalidDocument,
+		}
+	}
+
+	contentType := ctx.Request.Header.Get(goservectx.ContentType)
+	err = patcher.ApplyLocale(contentType, document, target, ctx.Locale())
+	if errors.Is(err, patch.ErrUnsupportedMediaType) {
+		(*ctx.Writer).Header().Set("Accept-Patch", patch.AcceptPatch)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/patch"
+	"github.com/softwareplace/goserve/problem"
+)
+
+func TestPatchRequestBody(t *testing.T) {
+	patcher := patch.New("/name")
+
+	serve := func(contentType string, body string) (*httptest.ResponseRecorder, petRequest, error) {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/pets/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+
+		target := petRequest{Name: "Rex"}
+		ctx := context.Of[*context.DefaultContext](recorder, req, "test")
+		err := PatchRequestBody(ctx, patcher, &target)
+		return recorder, target, err
+	}
+
+	t.Run("should apply a merge patch", func(t *testing.T) {
+		_, target, err := serve(patch.MergePatch, `{"name": "Max"}`)
+
+		require.NoError(t, err)
+		require.Equal(t, "Max", target.Name)
+	})
+
+	t.Run("should apply a JSON patch", func(t *testing.T) {
+		_, target, err := serve(patch.JSONPatch, `[{"op": "replace", "path": "/name", "value": "Max"}]`)
+
+		require.NoError(t, err)
+		require.Equal(t, "Max", target.Name)
+	})
+
+	t.Run("should list the supported media types on an unsupported content type", func(t *testing.T) {
+		recorder, target, err := serve("application/json", `{"name": "Max"}`)
+
+		require.Equal(t, http.StatusUnsupportedMediaType, problem.From(err).Status)
+		require.Equal(t, patch.AcceptPatch, recorder.Header().Get("Accept-Patch"))
+		require.Equal(t, "Rex", target.Name)
+	})
+
+	t.Run("should reject oversized documents", func(t *testing.T) {
+		_, _, err := serve(patch.MergePatch, `{"name": "`+strings.Repeat("x", patch.MaxDocumentSize)+`"}`)
+
+		require.ErrorIs(t, err, patch.ErrInvalidDocument)
+	})
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,6 +26,7 @@ type Config struct {
 	Query              map[string][]string
 	Body               any
 	ExpectedStatusCode int
+	Context            context.Context // Context, when set, bounds the request, e.g. with the deadline of the incoming request.
 }
 
 // Service represents a request service
@@ -179,6 +181,13 @@ func (config *Config) WithBody(body any) *Config {
 	return config
 }
 
+// WithContext binds the request to the given context, so it is canceled with it.
+// Use ctx.Context() of the incoming request to propagate its deadline and cancellation.
+func (config *Config) WithContext(ctx context.Context) *Config {
+	config.Context = ctx
+	return config
+}
+
 // WithExpectedStatusCode adds an expected status code to the request
 func (config *Config) WithExpectedStatusCode(expectedStatusCode int) *Config {
 	config.ExpectedStatusCode = expectedStatusCode
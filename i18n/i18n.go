@@ -0,0 +1,251 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/language"
+
+	"github.com/softwareplace/goserve/env"
+)
+
+const (
+	// DefaultLocale is the locale used when the request does not ask for a supported one.
+	DefaultLocale = "en"
+
+	// ValidationPrefix is the key prefix of the validation messages, such as validation.required.
+	ValidationPrefix = "validation."
+)
+
+//go:embed locales/*.json
+var bundles embed.FS
+
+// Catalog holds the messages of each locale and selects the best locale for an Accept-Language
+// header. Keys of goserve's own messages are the English messages themselves, such as
+// "Unauthorized", so unknown messages are answered as they are. Validation messages use the
+// validation.<tag> keys and the {0}, {1} placeholders of the validator translations.
+//
+// A Catalog is safe for concurrent use.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+	locales  []string
+	matcher  language.Matcher
+	fallback string
+}
+
+var (
+	defaultCatalog     *Catalog
+	defaultCatalogOnce sync.Once
+)
+
+// NewCatalog creates an empty catalog that falls back to the given locale.
+func NewCatalog(fallback string) *Catalog {
+	c := &Catalog{
+		messages: make(map[string]map[string]string),
+		fallback: fallback,
+	}
+	c.messages[fallback] = make(map[string]string)
+	c.rebuild()
+	return c
+}
+
+// Default returns the catalog used by goserve. It holds the built-in bundles for en, pt-BR, es, fr
+// and de, plus the `.json` files of the directory set in the I18N_LOCALES_DIR environment variable.
+func Default() *Catalog {
+	defaultCatalogOnce.Do(func() {
+		defaultCatalog = NewCatalog(DefaultLocale)
+
+		entries, _ := bundles.ReadDir("locales")
+		for _, entry := range entries {
+			data, err := bundles.ReadFile("locales/" + entry.Name())
+			if err == nil {
+				err = defaultCatalog.AddJSON(localeOf(entry.Name()), data)
+			}
+			if err != nil {
+				log.Errorf("failed to load the built-in %s messages: %v", entry.Name(), err)
+			}
+		}
+
+		if dir := env.GetEnvOrDefault("I18N_LOCALES_DIR", ""); dir != "" {
+			if err := defaultCatalog.LoadDir(dir); err != nil {
+				log.Errorf("failed to load the messages of I18N_LOCALES_DIR %s: %v", dir, err)
+			}
+		}
+	})
+	return defaultCatalog
+}
+
+// Add merges the messages into the locale, replacing the messages already stored with the same keys.
+//
+// Parameters:
+//   - locale: A BCP 47 locale, such as pt-BR or es.
+//   - messages: The messages keyed by the English message or by validation.<tag>.
+//
+// Example usage:
+//
+//	i18n.Default().Add("pt-BR", map[string]string{
+//		"Pet not found": "Pet não encontrado",
+//		"validation.sku": "{0} deve começar com SKU-",
+//	})
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	locale = canonical(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bundle, ok := c.messages[locale]
+	if !ok {
+		bundle = make(map[string]string, len(messages))
+		c.messages[locale] = bundle
+	}
+
+	for key, message := range messages {
+		bundle[key] = message
+	}
+	c.rebuild()
+}
+
+// AddJSON merges a flat JSON object of messages into the locale. See Add.
+func (c *Catalog) AddJSON(locale string, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("invalid %s messages: %w", locale, err)
+	}
+
+	c.Add(locale, messages)
+	return nil
+}
+
+// LoadFile merges the messages of a JSON file named after its locale, such as pt-BR.json.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return c.AddJSON(localeOf(path), data)
+}
+
+// LoadDir merges the messages of every `.json` file of the directory. See LoadFile.
+func (c *Catalog) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := c.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales of the catalog, sorted.
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string{}, c.locales...)
+}
+
+// Fallback returns the locale used when no other locale matches.
+func (c *Catalog) Fallback() string {
+	return c.fallback
+}
+
+// Match selects the best locale of the catalog for an Accept-Language header, honouring quality
+// values and falling back from regional variants, such as pt-PT, to the closest locale, such as
+// pt-BR. It returns the fallback locale when the header is empty, invalid or has no supported language.
+func (c *Catalog) Match(acceptLanguage string) string {
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return c.fallback
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, index := language.MatchStrings(c.matcher, acceptLanguage)
+	if index < 0 || index >= len(c.locales) {
+		return c.fallback
+	}
+	return c.locales[index]
+}
+
+// Message returns the message of the key in the locale, without any fallback.
+func (c *Catalog) Message(locale string, key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	message, ok := c.messages[canonical(locale)][key]
+	return message, ok
+}
+
+// Translate returns the message of the key in the locale, or in the fallback locale, with the
+// {0}, {1}, ... placeholders replaced by args. The key itself is used when no message is found.
+func (c *Catalog) Translate(locale string, key string, args ...string) string {
+	message, ok := c.Message(locale, key)
+	if !ok {
+		message, ok = c.Message(c.fallback, key)
+	}
+	if !ok {
+		message = key
+	}
+	return Format(message, args...)
+}
+
+func (c *Catalog) rebuild() {
+	c.locales = make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		if locale != c.fallback {
+			c.locales = append(c.locales, locale)
+		}
+	}
+	sort.Strings(c.locales)
+
+	// The first tag is the default of the matcher, so the fallback goes first.
+	c.locales = append([]string{c.fallback}, c.locales...)
+
+	tags := make([]language.Tag, 0, len(c.locales))
+	for _, locale := range c.locales {
+		tags = append(tags, language.Make(locale))
+	}
+	c.matcher = language.NewMatcher(tags)
+}
+
+// Format replaces the {0}, {1}, ... placeholders of the message by args.
+func Format(message string, args ...string) string {
+	for i, arg := range args {
+		message = strings.ReplaceAll(message, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return message
+}
+
+// Match selects the best locale of the Default catalog for an Accept-Language header.
+func Match(acceptLanguage string) string {
+	return Default().Match(acceptLanguage)
+}
+
+// Translate translates the key using the Default catalog. See Catalog.Translate.
+func Translate(locale string, key string, args ...string) string {
+	return Default().Translate(locale, key, args...)
+}
+
+func localeOf(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func canonical(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Run("should select the best supported locale from Accept-Language", func(t *testing.T) {
+		catalog := Default()
+
+		require.Equal(t, "pt-BR", catalog.Match("pt-BR,pt;q=0.9,en;q=0.8"))
+		require.Equal(t, "pt-BR", catalog.Match("pt-PT"))
+		require.Equal(t, "de", catalog.Match("it;q=0.9, de-CH;q=0.8"))
+		require.Equal(t, "fr", catalog.Match("en;q=0.5, fr"))
+		require.Equal(t, DefaultLocale, catalog.Match("ja"))
+		require.Equal(t, DefaultLocale, catalog.Match(""))
+		require.Equal(t, DefaultLocale, catalog.Match("not a language;;q=x"))
+	})
+
+	t.Run("should translate goserve messages and keep unknown ones", func(t *testing.T) {
+		require.Equal(t, "Não autorizado", Translate("pt-BR", "Unauthorized"))
+		require.Equal(t, "Datos de la solicitud no válidos", Translate("es", "Invalid request data"))
+		require.Equal(t, "Unauthorized", Translate("en", "Unauthorized"))
+		require.Equal(t, "Pet not found", Translate("de", "Pet not found"))
+		require.Equal(t, "Name ist ein Pflichtfeld", Translate("de", "validation.required", "Name"))
+	})
+
+	t.Run("should load more locales from files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "it.json"), []byte(`{"Unauthorized": "Non autorizzato"}`), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pt-BR.json"), []byte(`{"Pet not found": "Pet não encontrado"}`), 0o600))
+
+		catalog := NewCatalog(DefaultLocale)
+		require.NoError(t, catalog.LoadDir(dir))
+
+		require.Equal(t, []string{"en", "it", "pt-BR"}, catalog.Locales())
+		require.Equal(t, "it", catalog.Match("it-IT"))
+		require.Equal(t, "Non autorizzato", catalog.Translate("it", "Unauthorized"))
+		require.Equal(t, "Pet não encontrado", catalog.Translate("pt-BR", "Pet not found"))
+	})
+
+	t.Run("should reject invalid files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "es.json")
+		require.NoError(t, os.WriteFile(path, []byte(`["not", "an", "object"]`), 0o600))
+		require.Error(t, NewCatalog(DefaultLocale).LoadFile(path))
+	})
+}
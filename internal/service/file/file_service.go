@@ -1,8 +1,12 @@
 package file
 
 import (
+	"net/http"
+	"os"
 	"sync"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/softwareplace/goserve/internal/gen"
 
 	goservectx "github.com/softwareplace/goserve/context"
@@ -16,6 +20,20 @@ var (
 	serviceOnce     sync.Once
 )
 
+var imageUploadLimits = goservectx.UploadLimits{
+	MaxFileSize:  5 << 20,
+	MaxFiles:     1,
+	AllowedTypes: []string{"image/*"},
+}
+
+// uploadErrorMessages answers the upload failures with a fixed message per status, the error
+// itself is only logged.
+var uploadErrorMessages = map[int]string{
+	http.StatusRequestEntityTooLarge: "Failed to upload file: send a single image of at most 5MB",
+	http.StatusUnsupportedMediaType:  "Failed to upload file: only images are allowed",
+	http.StatusBadRequest:            "Failed to upload file: invalid multipart request",
+}
+
 func New() *Service {
 	serviceOnce.Do(func() {
 		serviceInstance = &Service{}
@@ -24,5 +42,18 @@ func New() *Service {
 }
 
 func (s Service) UploadFile(request gen.UploadFileClientRequest, ctx *goservectx.Request[*goservectx.DefaultContext]) {
-	ctx.BadRequest("Failed to upload file")
+	result, err := ctx.UploadToDir(imageUploadLimits, os.TempDir())
+	if err != nil {
+		log.Errorf("[%s]:: UPLOAD/FILE: failed to upload file: %v", ctx.GetSessionId(), err)
+		status := goservectx.UploadErrorStatus(err)
+		ctx.Error(uploadErrorMessages[status], status)
+		return
+	}
+
+	if len(result.Files) == 0 {
+		ctx.BadRequest("Failed to upload file: no file was sent")
+		return
+	}
+
+	ctx.Ok(result.Files[0])
 }
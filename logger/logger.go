@@ -50,6 +50,11 @@ func init() {
 	Formatter = NestedLogFormatter()
 }
 
+// SetLogDir sets the directory of the log files, overriding LOG_DIR. It must be called before LogSetup.
+func SetLogDir(dir string) {
+	logDirPath = dir
+}
+
 // LogSetup initializes the logging system by creating a log file and starting a monitoring routine for file rotation.
 // Default:
 //   - LumberjackLogger used as default. You can also change it
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// IssueKind identifies the kind of mismatch found by CheckConformance.
+type IssueKind string
+
+const (
+	// MissingHandler reports an operation documented in the spec without a registered route.
+	MissingHandler IssueKind = "MISSING_HANDLER"
+	// UndocumentedRoute reports a registered route that is not documented in the spec.
+	UndocumentedRoute IssueKind = "UNDOCUMENTED_ROUTE"
+	// ScopeMismatch reports an operation whose declared security scopes differ from the enforced roles.
+	ScopeMismatch IssueKind = "SCOPE_MISMATCH"
+)
+
+var (
+	pathParamMatcher = regexp.MustCompile(`\{[^/]*}`)
+)
+
+// Route describes a registered route as seen by the conformance check.
+type Route struct {
+	Method  string   // Method is the HTTP method of the route.
+	Path    string   // Path is the full route path template, including the context path.
+	Roles   []string // Roles are the roles enforced for the route.
+	Public  bool     // Public indicates whether the route was registered as a public route.
+	Builtin bool     // Builtin marks goserve's own resources, such as health and login, which may stay undocumented.
+}
+
+// Issue describes a single mismatch between the spec and the registered routes.
+type Issue struct {
+	Kind           IssueKind `json:"kind"`
+	Method         string    `json:"method"`
+	Path           string    `json:"path"`
+	OperationId    string    `json:"operationId,omitempty"`
+	DeclaredScopes []string  `json:"declaredScopes,omitempty"`
+	EnforcedRoles  []string  `json:"enforcedRoles,omitempty"`
+	Detail         string    `json:"detail"`
+}
+
+// ConformanceReport is the structured result of CheckConformance.
+type ConformanceReport struct {
+	Issues []Issue `json:"issues"`
+}
+
+// Ok reports whether no issue was found.
+func (r ConformanceReport) Ok() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns an error listing every issue, or nil when the report has no issues.
+// It is handy as a test helper:
+//
+//	require.NoError(t, api.Conformance().Err())
+func (r ConformanceReport) Err() error {
+	if r.Ok() {
+		return nil
+	}
+
+	var lines []string
+	for _, issue := range r.Issues {
+		lines = append(lines, fmt.Sprintf("%s %s %s: %s", issue.Kind, issue.Method, issue.Path, issue.Detail))
+	}
+	return fmt.Errorf("spec conformance failed with %d issue(s):\n%s", len(r.Issues), strings.Join(lines, "\n"))
+}
+
+// CheckConformance compares the operations of the spec with the registered routes.
+//
+// It reports:
+//   - MissingHandler for every documented operation without a matching route.
+//   - UndocumentedRoute for every route without a matching documented operation, except builtin ones.
+//   - ScopeMismatch for every operation whose declared scopes differ from the route roles,
+//     including public routes for operations that declare scopes and vice versa.
+//
+// Paths are matched by their template, ignoring path parameter names and patterns,
+// so `/pets/{id}` matches `/pets/{petId:[0-9]+}`.
+//
+// Parameters:
+//   - spec: The OpenAPI document, with paths already prefixed by the context path.
+//   - routes: The registered routes.
+//
+// Returns:
+//   - ConformanceReport: The issues found, sorted by path and method.
+func CheckConformance(spec *openapi3.T, routes []Route) ConformanceReport {
+	report := ConformanceReport{Issues: []Issue{}}
+
+	registered := map[string]Route{}
+	for _, route := range routes {
+		registered[RouteKey(route.Method, route.Path)] = route
+	}
+
+	documented := map[string]bool{}
+
+	if spec != nil && spec.Paths != nil {
+		for path, pathItem := range spec.Paths.Map() {
+			for method, operation := range pathItem.Operations() {
+				key := RouteKey(method, path)
+				documented[key] = true
+
+				route, found := registered[key]
+				if !found {
+					report.Issues = append(report.Issues, Issue{
+						Kind:        MissingHandler,
+						Method:      method,
+						Path:        path,
+						OperationId: operation.OperationID,
+						Detail:      "documented operation has no registered handler",
+					})
+					continue
+				}
+
+				if issue, ok := scopeIssue(spec, operation, route, method, path); ok {
+					report.Issues = append(report.Issues, issue)
+				}
+			}
+		}
+	}
+
+	for key, route := range registered {
+		if !documented[key] && !route.Builtin {
+			report.Issues = append(report.Issues, Issue{
+				Kind:          UndocumentedRoute,
+				Method:        route.Method,
+				Path:          route.Path,
+				EnforcedRoles: route.Roles,
+				Detail:        "registered route is not documented in the spec",
+			})
+		}
+	}
+
+	sort.Slice(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Path != report.Issues[j].Path {
+			return report.Issues[i].Path < report.Issues[j].Path
+		}
+		if report.Issues[i].Method != report.Issues[j].Method {
+			return report.Issues[i].Method < report.Issues[j].Method
+		}
+		return report.Issues[i].Kind < report.Issues[j].Kind
+	})
+
+	return report
+}
+
+func scopeIssue(spec *openapi3.T, operation *openapi3.Operation, route Route, method, path string) (Issue, bool) {
+	declared := Scopes(spec, operation)
+
+	issue := Issue{
+		Kind:           ScopeMismatch,
+		Method:         method,
+		Path:           path,
+		OperationId:    operation.OperationID,
+		DeclaredScopes: declared,
+		EnforcedRoles:  route.Roles,
+	}
+
+	switch {
+	case len(declared) > 0 && route.Public:
+		issue.Detail = "operation declares security scopes but the route is public"
+	case len(declared) == 0 && len(route.Roles) > 0:
+		issue.Detail = "operation declares no security scopes but the route enforces roles"
+	case !sameSet(declared, route.Roles):
+		issue.Detail = "declared security scopes differ from the enforced roles"
+	default:
+		return Issue{}, false
+	}
+
+	return issue, true
+}
+
+// RouteKey returns the key matching a route with the documented operation of the same method and
+// path template, ignoring path parameter names and patterns, as CheckConformance does.
+func RouteKey(method, path string) string {
+	return strings.ToUpper(method) + "::" + pathParamMatcher.ReplaceAllString(path, "{}")
+}
+
+func sameSet(a, b []string) bool {
+	set := map[string]bool{}
+	for _, value := range a {
+		set[value] = true
+	}
+
+	other := map[string]bool{}
+	for _, value := range b {
+		if !set[value] {
+			return false
+		}
+		other[value] = true
+	}
+
+	return len(set) == len(other)
+}
@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConformance(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	t.Run("should return no issue when routes match the spec", func(t *testing.T) {
+		report := CheckConformance(spec, []Route{
+			{Method: "GET", Path: "/pets/{petId}", Roles: []string{"read:pets"}},
+			{Method: "POST", Path: "/pets", Public: true},
+			{Method: "GET", Path: "/health", Public: true, Builtin: true},
+		})
+
+		require.True(t, report.Ok())
+		require.NoError(t, report.Err())
+	})
+
+	t.Run("should report missing handlers, undocumented routes and scope mismatches", func(t *testing.T) {
+		report := CheckConformance(spec, []Route{
+			{Method: "GET", Path: "/pets/{id:[0-9]+}", Public: true},
+			{Method: "DELETE", Path: "/pets/{id}", Roles: []string{"write:pets"}},
+		})
+
+		require.Equal(t, []Issue{
+			{
+				Kind:        MissingHandler,
+				Method:      "POST",
+				Path:        "/pets",
+				OperationId: "addPet",
+				Detail:      "documented operation has no registered handler",
+			},
+			{
+				Kind:          UndocumentedRoute,
+				Method:        "DELETE",
+				Path:          "/pets/{id}",
+				EnforcedRoles: []string{"write:pets"},
+				Detail:        "registered route is not documented in the spec",
+			},
+			{
+				Kind:           ScopeMismatch,
+				Method:         "GET",
+				Path:           "/pets/{id}",
+				OperationId:    "getPet",
+				DeclaredScopes: []string{"read:pets"},
+				Detail:         "operation declares security scopes but the route is public",
+			},
+		}, report.Issues)
+		require.Error(t, report.Err())
+	})
+
+	t.Run("should report different scopes and roles", func(t *testing.T) {
+		report := CheckConformance(spec, []Route{
+			{Method: "GET", Path: "/pets/{id}", Roles: []string{"read:pets", "admin"}},
+			{Method: "POST", Path: "/pets", Roles: []string{"write:pets"}},
+		})
+
+		require.Len(t, report.Issues, 2)
+		require.Equal(t, "operation declares no security scopes but the route enforces roles", report.Issues[0].Detail)
+		require.Equal(t, "declared security scopes differ from the enforced roles", report.Issues[1].Detail)
+	})
+}
@@ -0,0 +1,266 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// maxSchemaDepth limits how deep the schema synthesizer walks nested and recursive schemas.
+	maxSchemaDepth = 8
+)
+
+var (
+	// ErrResponseNotDocumented is returned when the requested status code is not documented by the operation.
+	ErrResponseNotDocumented = errors.New("response not documented")
+
+	// ErrExampleNotDocumented is returned when the requested example name is not documented by the response.
+	ErrExampleNotDocumented = errors.New("example not documented")
+)
+
+// MockResponse is a response built from the documentation of an OpenAPI operation.
+type MockResponse struct {
+	Status      int    // Status is the HTTP status code of the documented response.
+	ContentType string // ContentType is the documented media type, empty when the response has no body.
+	Body        any    // Body is the documented example or a payload synthesized from the response schema.
+}
+
+// ResponseFor builds a MockResponse for the given operation, honouring the client preference.
+//
+// The response is chosen as follows:
+//   - preference.Code, when set, must match a documented status code.
+//   - Otherwise the lowest documented 2xx response is used, then `default`, then the lowest documented code.
+//
+// The body is chosen as follows:
+//   - preference.Example, when set, must match a documented example name.
+//   - Otherwise the media type `example`, then the first documented `examples` entry by name.
+//   - Otherwise a payload is synthesized from the media type schema.
+//
+// Parameters:
+//   - operation: The OpenAPI operation to mock.
+//   - preference: The response preference parsed from the Prefer header.
+//
+// Returns:
+//   - *MockResponse: The response to send.
+//   - error: ErrResponseNotDocumented or ErrExampleNotDocumented when the preference can't be satisfied.
+func ResponseFor(operation *openapi3.Operation, preference Preference) (*MockResponse, error) {
+	if operation == nil || operation.Responses == nil || operation.Responses.Len() == 0 {
+		return nil, fmt.Errorf("%w: operation has no responses", ErrResponseNotDocumented)
+	}
+
+	status, ref := pickResponse(operation.Responses, preference.Code)
+	if ref == nil || ref.Value == nil {
+		return nil, fmt.Errorf("%w: status %d", ErrResponseNotDocumented, preference.Code)
+	}
+
+	mock := &MockResponse{Status: status}
+
+	contentType, mediaType := pickMediaType(ref.Value.Content)
+	if mediaType == nil {
+		return mock, nil
+	}
+
+	mock.ContentType = contentType
+
+	if preference.Example != "" {
+		example, ok := mediaType.Examples[preference.Example]
+		if !ok || example == nil || example.Value == nil {
+			return nil, fmt.Errorf("%w: %s", ErrExampleNotDocumented, preference.Example)
+		}
+		mock.Body = example.Value.Value
+		return mock, nil
+	}
+
+	if mediaType.Example != nil {
+		mock.Body = mediaType.Example
+		return mock, nil
+	}
+
+	if names := sortedKeys(mediaType.Examples); len(names) > 0 {
+		if example := mediaType.Examples[names[0]]; example != nil && example.Value != nil {
+			mock.Body = example.Value.Value
+			return mock, nil
+		}
+	}
+
+	mock.Body = SchemaExample(mediaType.Schema)
+	return mock, nil
+}
+
+// SchemaExample synthesizes a payload that satisfies the given schema.
+// Documented `example`, `default` and `enum` values are preferred over generated ones.
+//
+// Parameters:
+//   - ref: The schema reference to synthesize a payload from.
+//
+// Returns:
+//   - any: A JSON-compatible value, or nil when the schema is empty.
+func SchemaExample(ref *openapi3.SchemaRef) any {
+	return schemaExample(ref, 0)
+}
+
+func schemaExample(ref *openapi3.SchemaRef, depth int) any {
+	if ref == nil || ref.Value == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	schema := ref.Value
+
+	if schema.Example != nil {
+		return schema.Example
+	}
+
+	if schema.Default != nil {
+		return schema.Default
+	}
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, item := range schema.AllOf {
+			if value, ok := schemaExample(item, depth+1).(map[string]any); ok {
+				for key, v := range value {
+					merged[key] = v
+				}
+			}
+		}
+		return merged
+	}
+
+	if len(schema.OneOf) > 0 {
+		return schemaExample(schema.OneOf[0], depth+1)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return schemaExample(schema.AnyOf[0], depth+1)
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		return stringExample(schema)
+	case schema.Type.Is(openapi3.TypeInteger):
+		return int64(numberExample(schema))
+	case schema.Type.Is(openapi3.TypeNumber):
+		return numberExample(schema)
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return true
+	case schema.Type.Is(openapi3.TypeArray):
+		if item := schemaExample(schema.Items, depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case schema.Type.Is(openapi3.TypeObject) || len(schema.Properties) > 0:
+		return objectExample(schema, depth)
+	}
+
+	return nil
+}
+
+func objectExample(schema *openapi3.Schema, depth int) map[string]any {
+	object := map[string]any{}
+	for _, name := range sortedKeys(schema.Properties) {
+		if value := schemaExample(schema.Properties[name], depth+1); value != nil {
+			object[name] = value
+		}
+	}
+	return object
+}
+
+func stringExample(schema *openapi3.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return time.Unix(0, 0).UTC().Format(time.RFC3339)
+	case "date":
+		return time.Unix(0, 0).UTC().Format(time.DateOnly)
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	}
+
+	example := "string"
+	if schema.MinLength > uint64(len(example)) {
+		example += strings.Repeat("x", int(schema.MinLength)-len(example))
+	}
+	return example
+}
+
+func numberExample(schema *openapi3.Schema) float64 {
+	if schema.Min != nil {
+		return *schema.Min
+	}
+	if schema.Max != nil && *schema.Max < 0 {
+		return *schema.Max
+	}
+	return 0
+}
+
+func pickResponse(responses *openapi3.Responses, code int) (int, *openapi3.ResponseRef) {
+	if code != 0 {
+		return code, responses.Status(code)
+	}
+
+	var codes []int
+	for key := range responses.Map() {
+		if status, err := strconv.Atoi(key); err == nil {
+			codes = append(codes, status)
+		}
+	}
+	sort.Ints(codes)
+
+	for _, status := range codes {
+		if status >= 200 && status < 300 {
+			return status, responses.Status(status)
+		}
+	}
+
+	if ref := responses.Default(); ref != nil {
+		return http.StatusOK, ref
+	}
+
+	if len(codes) > 0 {
+		return codes[0], responses.Status(codes[0])
+	}
+
+	return 0, nil
+}
+
+func pickMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+
+	if mediaType := content.Get("application/json"); mediaType != nil {
+		return "application/json", mediaType
+	}
+
+	names := sortedKeys(content)
+	for _, name := range names {
+		if strings.HasSuffix(name, "+json") {
+			return name, content[name]
+		}
+	}
+
+	return names[0], content[names[0]]
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,160 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      operationId: getPet
+      security:
+        - petstore_auth:
+            - read:pets
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        '404':
+          description: not found
+          content:
+            application/json:
+              examples:
+                missing:
+                  value:
+                    message: pet not found
+                gone:
+                  value:
+                    message: pet is gone
+  /pets:
+    post:
+      operationId: addPet
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              example:
+                id: 10
+                name: doggie
+        '204':
+          description: no content
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        name:
+          type: string
+        status:
+          type: string
+          enum: [available, sold]
+        tags:
+          type: array
+          items:
+            type: string
+        createdAt:
+          type: string
+          format: date-time
+`
+
+func loadTestSpec(t *testing.T) *openapi3.T {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestParsePreference(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected Preference
+	}{
+		{"empty header", "", Preference{}},
+		{"code only", "code=404", Preference{Code: 404}},
+		{"code and example", `code=404, example="missing"`, Preference{Code: 404, Example: "missing"}},
+		{"unknown preferences are ignored", "return=minimal; example=gone", Preference{Example: "gone"}},
+		{"malformed code is ignored", "code=abc", Preference{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, ParsePreference(tt.value))
+		})
+	}
+}
+
+func TestResponseFor(t *testing.T) {
+	spec := loadTestSpec(t)
+	getPet := spec.Paths.Find("/pets/{id}").Get
+	addPet := spec.Paths.Find("/pets").Post
+
+	t.Run("should synthesize the body from the schema when no example is documented", func(t *testing.T) {
+		response, err := ResponseFor(getPet, Preference{})
+		require.NoError(t, err)
+		require.Equal(t, 200, response.Status)
+		require.Equal(t, "application/json", response.ContentType)
+		require.Equal(t, map[string]any{
+			"id":        int64(1),
+			"name":      "string",
+			"status":    "available",
+			"tags":      []any{"string"},
+			"createdAt": "1970-01-01T00:00:00Z",
+		}, response.Body)
+	})
+
+	t.Run("should use the documented example of the first success response", func(t *testing.T) {
+		response, err := ResponseFor(addPet, Preference{})
+		require.NoError(t, err)
+		require.Equal(t, 201, response.Status)
+		require.Equal(t, map[string]any{"id": float64(10), "name": "doggie"}, response.Body)
+	})
+
+	t.Run("should pick the preferred code and example", func(t *testing.T) {
+		response, err := ResponseFor(getPet, Preference{Code: 404, Example: "gone"})
+		require.NoError(t, err)
+		require.Equal(t, 404, response.Status)
+		require.Equal(t, map[string]any{"message": "pet is gone"}, response.Body)
+	})
+
+	t.Run("should use the first example by name when none is preferred", func(t *testing.T) {
+		response, err := ResponseFor(getPet, Preference{Code: 404})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"message": "pet is gone"}, response.Body)
+	})
+
+	t.Run("should return no body for responses without content", func(t *testing.T) {
+		response, err := ResponseFor(addPet, Preference{Code: 204})
+		require.NoError(t, err)
+		require.Equal(t, 204, response.Status)
+		require.Nil(t, response.Body)
+	})
+
+	t.Run("should fail when the preference is not documented", func(t *testing.T) {
+		_, err := ResponseFor(getPet, Preference{Code: 500})
+		require.ErrorIs(t, err, ErrResponseNotDocumented)
+
+		_, err = ResponseFor(getPet, Preference{Code: 404, Example: "unknown"})
+		require.ErrorIs(t, err, ErrExampleNotDocumented)
+	})
+}
+
+func TestScopes(t *testing.T) {
+	spec := loadTestSpec(t)
+	require.Equal(t, []string{"read:pets"}, Scopes(spec, spec.Paths.Find("/pets/{id}").Get))
+	require.Empty(t, Scopes(spec, spec.Paths.Find("/pets").Post))
+}
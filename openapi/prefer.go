@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// PreferHeader is the request header used by clients to pick a specific documented response
+	// from a mocked operation, e.g. `Prefer: code=404, example=notFound`.
+	PreferHeader = "Prefer"
+)
+
+// Preference holds the response selection requested through the Prefer header.
+type Preference struct {
+	Code    int    // Code is the documented status code to answer with. Zero means the first success response.
+	Example string // Example is the name of the documented example to answer with. Empty means the default example.
+}
+
+// ParsePreference parses a Prefer header value into a Preference.
+// Unknown or malformed preferences are ignored so that clients always get a response.
+//
+// Parameters:
+//   - value: The raw Prefer header value, e.g. `code=404, example=notFound`.
+//
+// Returns:
+//   - Preference: The parsed response preference.
+func ParsePreference(value string) Preference {
+	preference := Preference{}
+
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, val, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+
+		val = strings.Trim(strings.TrimSpace(val), `"`)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "code", "status":
+			if code, err := strconv.Atoi(val); err == nil {
+				preference.Code = code
+			}
+		case "example":
+			preference.Example = val
+		}
+	}
+
+	return preference
+}
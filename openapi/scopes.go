@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Scopes returns the security scopes declared for an operation, in declaration order and without duplicates.
+// When the operation does not declare its own security requirements, the document-level requirements are used.
+//
+// The result mirrors the roles the generated RequestServiceHandler registers for the operation:
+// an empty result means the operation is registered as a public route.
+//
+// Parameters:
+//   - spec: The OpenAPI document that owns the operation.
+//   - operation: The operation to inspect.
+//
+// Returns:
+//   - []string: The declared scopes.
+func Scopes(spec *openapi3.T, operation *openapi3.Operation) []string {
+	requirements := operation.Security
+	if requirements == nil && spec != nil {
+		requirements = &spec.Security
+	}
+
+	if requirements == nil {
+		return nil
+	}
+
+	var scopes []string
+	seen := map[string]bool{}
+	for _, requirement := range *requirements {
+		for _, name := range sortedKeys(requirement) {
+			for _, scope := range requirement[name] {
+				if !seen[scope] {
+					seen[scope] = true
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// Secured reports whether an operation declares a security requirement, with or without scopes,
+// such as a bearer or API key scheme. As for Scopes, the document-level requirements are used when
+// the operation does not declare its own. An empty requirement, `{}`, allows anonymous access.
+//
+// Parameters:
+//   - spec: The OpenAPI document that owns the operation.
+//   - operation: The operation to inspect.
+//
+// Returns:
+//   - bool: True if the operation requires any security scheme.
+func Secured(spec *openapi3.T, operation *openapi3.Operation) bool {
+	requirements := operation.Security
+	if requirements == nil && spec != nil {
+		requirements = &spec.Security
+	}
+
+	if requirements == nil {
+		return false
+	}
+
+	for _, requirement := range *requirements {
+		if len(requirement) > 0 {
+			return true
+		}
+	}
+	return false
+}
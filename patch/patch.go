@@ -0,0 +1,514 @@
+// Package patch applies JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7396) documents to typed
+// resources, restricted to an allow-list of paths and validated with their validate tags.
+package patch
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/softwareplace/goserve/problem"
+	"github.com/softwareplace/goserve/validator"
+)
+
+const (
+	// JSONPatch is the media type of RFC 6902 JSON Patch documents.
+	JSONPatch = "application/json-patch+json"
+
+	// MergePatch is the media type of RFC 7396 JSON Merge Patch documents.
+	MergePatch = "application/merge-patch+json"
+
+	// AcceptPatch is the value of the Accept-Patch header, listing the supported media types.
+	AcceptPatch = JSONPatch + ", " + MergePatch
+
+	// MaxDocumentSize is the maximum size, in bytes, of a patch document read from a request.
+	MaxDocumentSize = 1 << 20
+)
+
+// JSON Patch operations.
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+var (
+	// ErrUnsupportedMediaType is returned for a content type other than JSONPatch and MergePatch.
+	ErrUnsupportedMediaType = errors.New("unsupported patch media type")
+
+	// ErrInvalidDocument is returned for a malformed patch document or operation.
+	ErrInvalidDocument = errors.New("invalid patch document")
+
+	// ErrPathNotAllowed is returned when an operation touches a path outside the allow-list.
+	ErrPathNotAllowed = errors.New("patch path not allowed")
+
+	// ErrTestFailed is returned when a test operation does not match the resource.
+	ErrTestFailed = errors.New("patch test failed")
+
+	// ErrInvalidResult is returned when the patched document no longer fits the resource type.
+	ErrInvalidResult = errors.New("patched resource is invalid")
+)
+
+// Operation is a single RFC 6902 JSON Patch operation.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error reports why a patch could not be applied. It unwraps to one of the Err* sentinels, and
+// answers as a problem: 415 for ErrUnsupportedMediaType, 400 for ErrInvalidDocument, 403 for
+// ErrPathNotAllowed, 409 for ErrTestFailed and 422 for ErrInvalidResult.
+type Error struct {
+	Op     string // Op is the operation that failed, or merge for merge patches.
+	Path   string // Path is the JSON pointer the operation targets.
+	Reason string // Reason explains the failure.
+	Err    error  // Err is one of the Err* sentinels.
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	message := e.Err.Error()
+	if e.Op != "" {
+		message = fmt.Sprintf("%s: %s %s", message, e.Op, e.Path)
+	}
+	if e.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Reason)
+	}
+	return message
+}
+
+// Unwrap returns the Err* sentinel.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Problem answers the error as an `application/problem+json` response.
+func (e *Error) Problem() *problem.Problem {
+	status, slug := http.StatusBadRequest, "invalid-patch"
+	switch {
+	case errors.Is(e.Err, ErrUnsupportedMediaType):
+		status, slug = http.StatusUnsupportedMediaType, "unsupported-patch"
+	case errors.Is(e.Err, ErrPathNotAllowed):
+		status, slug = http.StatusForbidden, "patch-path-not-allowed"
+	case errors.Is(e.Err, ErrTestFailed):
+		status, slug = http.StatusConflict, "patch-test-failed"
+	case errors.Is(e.Err, ErrInvalidResult):
+		status, slug = http.StatusUnprocessableEntity, "invalid-patch-result"
+	}
+
+	p := problem.New(status, slug, e.Error()).Wrap(e)
+	if e.Op != "" {
+		p.With("op", e.Op).With("path", e.Path)
+	}
+	if status == http.StatusUnsupportedMediaType {
+		p.With("accept", AcceptPatch)
+	}
+	return p
+}
+
+// Patcher applies patch documents to resources. Only the paths of its allow-list, and their
+// children, can be changed. A Patcher is safe for concurrent use, so create it once per route.
+type Patcher struct {
+	allowed [][]string
+}
+
+// New creates a Patcher allowing changes to the given JSON pointers and their children. A `*`
+// token matches any member or array index, as in /items/*/quantity. When no path is given,
+// every path is allowed.
+//
+// Parameters:
+//   - allowed: The JSON pointers, such as /name or /address, that the patches may change.
+//
+// Returns:
+//   - *Patcher: The patcher.
+//
+// Example usage:
+//
+//	var petPatcher = patch.New("/name", "/tags", "/status")
+//
+//	func (s *Service) PatchPet(ctx *goservectx.Request[*Principal]) {
+//		pet := s.repository.Find(ctx.PathValueOf("id"))
+//		if err := goservehttp.PatchRequestBody(ctx, petPatcher, &pet); err != nil {
+//			ctx.Problem(err)
+//			return
+//		}
+//		ctx.Ok(s.repository.Save(pet))
+//	}
+func New(allowed ...string) *Patcher {
+	patcher := &Patcher{}
+	for _, pointer := range allowed {
+		tokens, err := parsePointer(pointer)
+		if err != nil {
+			panic(fmt.Sprintf("patch: invalid allowed path: %v", err))
+		}
+		patcher.allowed = append(patcher.allowed, tokens)
+	}
+	return patcher
+}
+
+// Allows reports whether the JSON pointer can be changed by a patch.
+func (p *Patcher) Allows(pointer string) bool {
+	tokens, err := parsePointer(pointer)
+	return err == nil && p.allows(tokens)
+}
+
+func (p *Patcher) allows(tokens []string) bool {
+	if len(p.allowed) == 0 {
+		return true
+	}
+	for _, allowed := range p.allowed {
+		if hasPrefix(tokens, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply applies the patch document of the given content type, JSONPatch or MergePatch, to the
+// target and validates the result with its validate tags. The target is only changed when the
+// whole patch applies and the result is valid.
+//
+// Parameters:
+//   - contentType: The content type of the document. Parameters, such as charset, are ignored.
+//   - document: The patch document.
+//   - target: A pointer to the resource to patch.
+//
+// Returns:
+//   - error: nil on success, an *Error for an unsupported, malformed, forbidden or failed patch,
+//     or validator.ValidationErrors when the result is invalid. Both answer as problems.
+func (p *Patcher) Apply(contentType string, document []byte, target any) error {
+	return p.ApplyLocale(contentType, document, target, "")
+}
+
+// ApplyLocale applies the patch document as Apply does, with the validation messages of the given
+// locale, usually the request locale.
+func (p *Patcher) ApplyLocale(contentType string, document []byte, target any, locale string) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+	case JSONPatch:
+		var operations []Operation
+		if err := json.Unmarshal(document, &operations); err != nil {
+			return &Error{Reason: err.Error(), Err: ErrInvalidDocument}
+		}
+		return p.apply(target, locale, func(doc any) (any, error) {
+			return p.applyOperations(doc, operations)
+		})
+	case MergePatch:
+		merge, err := decode(document)
+		if err != nil {
+			return &Error{Reason: err.Error(), Err: ErrInvalidDocument}
+		}
+		return p.apply(target, locale, func(doc any) (any, error) {
+			return p.applyMerge(doc, merge)
+		})
+	default:
+		return &Error{Reason: fmt.Sprintf("%q, expected %s", contentType, AcceptPatch), Err: ErrUnsupportedMediaType}
+	}
+}
+
+// ApplyOperations applies the JSON Patch operations to the target, as Apply does.
+func (p *Patcher) ApplyOperations(target any, operations ...Operation) error {
+	return p.apply(target, "", func(doc any) (any, error) {
+		return p.applyOperations(doc, operations)
+	})
+}
+
+func (p *Patcher) apply(target any, locale string, patch func(doc any) (any, error)) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("patch target must be a non nil pointer, got %T", target)
+	}
+
+	current, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decode(current)
+	if err != nil {
+		return err
+	}
+
+	doc, err = patch(doc)
+	if err != nil {
+		return err
+	}
+
+	patched, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// Decoding onto a copy of the target keeps its unexported and `json:"-"` fields, while clearing
+	// the JSON fields first drops those removed by the patch.
+	result := reflect.New(value.Elem().Type())
+	result.Elem().Set(value.Elem())
+	clearJSONFields(result.Elem())
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result.Interface()); err != nil {
+		return &Error{Reason: err.Error(), Err: ErrInvalidResult}
+	}
+
+	if reflect.Indirect(result.Elem()).Kind() == reflect.Struct {
+		if err := validator.Shared().StructLocale(result.Interface(), locale); err != nil {
+			return err
+		}
+	}
+
+	value.Elem().Set(result.Elem())
+	return nil
+}
+
+// clearJSONFields zeroes the fields of value that encoding/json reads, walking the nested structs
+// so that their unexported and `json:"-"` fields are kept. Pointers, slices and maps are zeroed
+// rather than walked, so that decoding never writes through the references shared with the target.
+func clearJSONFields(value reflect.Value) {
+	if value.Kind() != reflect.Struct || isUnmarshaler(value.Type()) {
+		if value.CanSet() {
+			value.SetZero()
+		}
+		return
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		if field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			clearJSONFields(value.Field(i))
+		}
+	}
+}
+
+func isUnmarshaler(t reflect.Type) bool {
+	pointer := reflect.PointerTo(t)
+	return pointer.Implements(reflect.TypeFor[json.Unmarshaler]()) ||
+		pointer.Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+func (p *Patcher) applyOperations(doc any, operations []Operation) (any, error) {
+	for _, operation := range operations {
+		var err error
+		doc, err = p.applyOperation(doc, operation)
+		if err != nil {
+			var patchErr *Error
+			if errors.As(err, &patchErr) {
+				return nil, err
+			}
+			return nil, &Error{Op: operation.Op, Path: operation.Path, Reason: err.Error(), Err: ErrInvalidDocument}
+		}
+	}
+	return doc, nil
+}
+
+func (p *Patcher) applyOperation(doc any, operation Operation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation.Op != OpTest && !p.allows(path) {
+		return nil, &Error{Op: operation.Op, Path: operation.Path, Err: ErrPathNotAllowed}
+	}
+
+	switch operation.Op {
+	case OpAdd, OpReplace, OpTest:
+		if operation.Value == nil {
+			return nil, fmt.Errorf("missing value")
+		}
+
+		value, err := decode(operation.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch operation.Op {
+		case OpAdd:
+			return set(doc, path, value, addTo(value))
+		case OpReplace:
+			return set(doc, path, value, replaceIn(value))
+		}
+
+		current, err := get(doc, path)
+		if err != nil || !equal(current, value) {
+			return nil, &Error{Op: operation.Op, Path: operation.Path, Err: ErrTestFailed}
+		}
+		return doc, nil
+
+	case OpRemove:
+		if len(path) == 0 {
+			return nil, fmt.Errorf("cannot remove the whole document")
+		}
+		return update(doc, path, removeFrom)
+
+	case OpMove, OpCopy:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == OpCopy {
+			value = deepCopy(value)
+			return set(doc, path, value, addTo(value))
+		}
+
+		if !p.allows(from) {
+			return nil, &Error{Op: operation.Op, Path: operation.From, Err: ErrPathNotAllowed}
+		}
+		if len(from) == 0 || (len(path) > len(from) && slices.Equal(path[:len(from)], from)) {
+			return nil, fmt.Errorf("cannot move %s into one of its children", operation.From)
+		}
+
+		doc, err = update(doc, from, removeFrom)
+		if err != nil {
+			return nil, err
+		}
+		return set(doc, path, value, addTo(value))
+
+	default:
+		return nil, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// set applies the update at path, or replaces the whole document when the path is empty.
+func set(doc any, path []string, value any, fn func(container any, key string) (any, error)) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, fn)
+}
+
+// applyMerge applies an RFC 7396 merge patch, after checking every path it changes.
+func (p *Patcher) applyMerge(doc any, merge any) (any, error) {
+	for _, path := range mergedPaths(merge, nil) {
+		if !p.allows(path) {
+			return nil, &Error{Op: "merge", Path: formatPointer(path), Err: ErrPathNotAllowed}
+		}
+	}
+	return mergeInto(doc, merge), nil
+}
+
+// mergedPaths lists the paths a merge patch changes: the members that are not objects, and the
+// empty objects.
+func mergedPaths(merge any, prefix []string) [][]string {
+	object, ok := merge.(map[string]any)
+	if !ok || (len(object) == 0 && prefix != nil) {
+		return [][]string{prefix}
+	}
+
+	var paths [][]string
+	for key, value := range object {
+		path := append(append([]string{}, prefix...), key)
+		paths = append(paths, mergedPaths(value, path)...)
+	}
+	return paths
+}
+
+func mergeInto(target any, merge any) any {
+	patch, ok := merge.(map[string]any)
+	if !ok {
+		return merge
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = mergeInto(object[key], value)
+	}
+	return object
+}
+
+func decode(document []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func deepCopy(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for key, item := range typed {
+			result[key] = deepCopy(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(typed))
+		for i, item := range typed {
+			result[i] = deepCopy(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// equal compares two decoded JSON values, numbers by value, as the test operation requires.
+func equal(a any, b any) bool {
+	switch typed := a.(type) {
+	case json.Number:
+		other, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errX := typed.Float64()
+		y, errY := other.Float64()
+		return errX == nil && errY == nil && x == y
+	case map[string]any:
+		other, ok := b.(map[string]any)
+		if !ok || len(typed) != len(other) {
+			return false
+		}
+		for key, item := range typed {
+			if otherItem, ok := other[key]; !ok || !equal(item, otherItem) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		other, ok := b.([]any)
+		if !ok || len(typed) != len(other) {
+			return false
+		}
+		for i := range typed {
+			if !equal(typed[i], other[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,270 @@
+package patch
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/problem"
+	"github.com/softwareplace/goserve/validator"
+)
+
+type address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type pet struct {
+	Name    string   `json:"name" validate:"required"`
+	Status  string   `json:"status,omitempty" validate:"omitempty,oneof=available sold"`
+	Tags    []string `json:"tags,omitempty"`
+	Age     int      `json:"age"`
+	Address *address `json:"address,omitempty"`
+	OwnerId string   `json:"ownerId,omitempty"`
+}
+
+type owner struct {
+	Name  string `json:"name"`
+	token string
+}
+
+// account has fields that JSON neither reads nor writes, which a patch must keep.
+type account struct {
+	Name     string `json:"name" validate:"required"`
+	Nickname string `json:"nickname,omitempty"`
+	Password string `json:"-"`
+	Owner    owner  `json:"owner"`
+	version  int
+}
+
+func newPet() pet {
+	return pet{
+		Name:    "Rex",
+		Status:  "available",
+		Tags:    []string{"dog", "small"},
+		Age:     3,
+		Address: &address{Street: "Main", City: "Lisbon"},
+		OwnerId: "42",
+	}
+}
+
+func TestPatcher_JSONPatch(t *testing.T) {
+	patcher := New()
+
+	t.Run("should apply every operation in order", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(JSONPatch, []byte(`[
+			{"op": "test", "path": "/age", "value": 3.0},
+			{"op": "replace", "path": "/name", "value": "Max"},
+			{"op": "add", "path": "/tags/1", "value": "friendly"},
+			{"op": "add", "path": "/tags/-", "value": "brown"},
+			{"op": "remove", "path": "/tags/0"},
+			{"op": "copy", "from": "/address/city", "path": "/address/street"},
+			{"op": "move", "from": "/ownerId", "path": "/status"}
+		]`), &target)
+
+		require.Error(t, err, "status 42 must fail the oneof rule")
+		require.Equal(t, newPet(), target, "the target must not change when the result is invalid")
+
+		err = patcher.Apply(JSONPatch, []byte(`[
+			{"op": "test", "path": "/age", "value": 3.0},
+			{"op": "replace", "path": "/name", "value": "Max"},
+			{"op": "add", "path": "/tags/1", "value": "friendly"},
+			{"op": "add", "path": "/tags/-", "value": "brown"},
+			{"op": "remove", "path": "/tags/0"},
+			{"op": "copy", "from": "/address/city", "path": "/address/street"},
+			{"op": "remove", "path": "/status"}
+		]`), &target)
+
+		require.NoError(t, err)
+		require.Equal(t, pet{
+			Name:    "Max",
+			Tags:    []string{"friendly", "small", "brown"},
+			Age:     3,
+			Address: &address{Street: "Lisbon", City: "Lisbon"},
+			OwnerId: "42",
+		}, target)
+	})
+
+	t.Run("should answer a conflict when a test operation fails", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(JSONPatch, []byte(`[
+			{"op": "test", "path": "/name", "value": "Max"},
+			{"op": "replace", "path": "/name", "value": "Bob"}
+		]`), &target)
+
+		require.ErrorIs(t, err, ErrTestFailed)
+		require.Equal(t, http.StatusConflict, problem.From(err).Status)
+		require.Equal(t, "Rex", target.Name)
+	})
+
+	t.Run("should reject malformed documents and operations", func(t *testing.T) {
+		for _, document := range []string{
+			`{"op": "add"}`,
+			`[{"op": "rename", "path": "/name"}]`,
+			`[{"op": "replace", "path": "/missing", "value": 1}]`,
+			`[{"op": "add", "path": "/tags/9", "value": "x"}]`,
+			`[{"op": "remove", "path": "name"}]`,
+			`[{"op": "replace", "path": "/name"}]`,
+			`[{"op": "move", "from": "/address", "path": "/address/street"}]`,
+		} {
+			target := newPet()
+			err := patcher.Apply(JSONPatch, []byte(document), &target)
+
+			require.ErrorIs(t, err, ErrInvalidDocument, document)
+			require.Equal(t, http.StatusBadRequest, problem.From(err).Status, document)
+		}
+	})
+
+	t.Run("should reject results that do not fit the resource type", func(t *testing.T) {
+		target := newPet()
+
+		err := patcher.Apply(JSONPatch, []byte(`[{"op": "add", "path": "/unknown", "value": 1}]`), &target)
+		require.ErrorIs(t, err, ErrInvalidResult)
+
+		err = patcher.Apply(JSONPatch, []byte(`[{"op": "replace", "path": "/age", "value": "old"}]`), &target)
+		require.ErrorIs(t, err, ErrInvalidResult)
+		require.Equal(t, http.StatusUnprocessableEntity, problem.From(err).Status)
+	})
+
+	t.Run("should validate the result", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(JSONPatch, []byte(`[{"op": "remove", "path": "/name"}]`), &target)
+
+		var validationErrors *validator.ValidationErrors
+		require.True(t, errors.As(err, &validationErrors))
+		require.Equal(t, "name", validationErrors.Fields[0].Field)
+		require.Equal(t, http.StatusUnprocessableEntity, problem.From(err).Status)
+		require.Equal(t, "Rex", target.Name)
+	})
+
+	t.Run("should apply typed operations", func(t *testing.T) {
+		target := newPet()
+		err := patcher.ApplyOperations(&target, Operation{Op: OpReplace, Path: "/age", Value: []byte(`4`)})
+
+		require.NoError(t, err)
+		require.Equal(t, 4, target.Age)
+	})
+}
+
+func TestPatcher_MergePatch(t *testing.T) {
+	patcher := New()
+
+	t.Run("should merge objects and remove null members", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(MergePatch+"; charset=utf-8", []byte(`{
+			"name": "Max",
+			"tags": ["cat"],
+			"status": null,
+			"address": {"city": "Porto"}
+		}`), &target)
+
+		require.NoError(t, err)
+		require.Equal(t, pet{
+			Name:    "Max",
+			Tags:    []string{"cat"},
+			Age:     3,
+			Address: &address{Street: "Main", City: "Porto"},
+			OwnerId: "42",
+		}, target)
+	})
+
+	t.Run("should keep the fields hidden from JSON", func(t *testing.T) {
+		target := account{Name: "Rex", Nickname: "R", Password: "secret", version: 2, Owner: owner{Name: "Ana", token: "t"}}
+		err := patcher.Apply(MergePatch, []byte(`{"name": "Max", "nickname": null, "owner": {"name": "Bia"}}`), &target)
+
+		require.NoError(t, err)
+		require.Equal(t, account{Name: "Max", Password: "secret", version: 2, Owner: owner{Name: "Bia", token: "t"}}, target)
+	})
+
+	t.Run("should leave the target untouched when the result is invalid", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(MergePatch, []byte(`{"name": "", "tags": ["cat", "dog"]}`), &target)
+
+		require.Error(t, err)
+		require.Equal(t, newPet(), target)
+	})
+
+	t.Run("should reject malformed documents", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(MergePatch, []byte(`{"name":`), &target)
+
+		require.ErrorIs(t, err, ErrInvalidDocument)
+	})
+}
+
+func TestPatcher_AllowList(t *testing.T) {
+	patcher := New("/name", "/tags", "/address/city", "/items/*/quantity")
+
+	t.Run("should allow the listed paths and their children", func(t *testing.T) {
+		require.True(t, patcher.Allows("/name"))
+		require.True(t, patcher.Allows("/tags/0"))
+		require.True(t, patcher.Allows("/address/city"))
+		require.True(t, patcher.Allows("/items/3/quantity"))
+		require.False(t, patcher.Allows("/address"))
+		require.False(t, patcher.Allows("/items/3/price"))
+		require.False(t, patcher.Allows(""))
+		require.True(t, New().Allows(""))
+	})
+
+	t.Run("should reject operations outside the allow-list", func(t *testing.T) {
+		for _, document := range []string{
+			`[{"op": "replace", "path": "/ownerId", "value": "7"}]`,
+			`[{"op": "replace", "path": "/address", "value": {"street": "x", "city": "y"}}]`,
+			`[{"op": "move", "from": "/ownerId", "path": "/name"}]`,
+			`[{"op": "replace", "path": "", "value": {"name": "x"}}]`,
+			`[{"op": "replace", "path": "/name", "value": "Max"}, {"op": "remove", "path": "/age"}]`,
+		} {
+			target := newPet()
+			err := patcher.Apply(JSONPatch, []byte(document), &target)
+
+			require.ErrorIs(t, err, ErrPathNotAllowed, document)
+			require.Equal(t, http.StatusForbidden, problem.From(err).Status, document)
+			require.Equal(t, newPet(), target, document)
+		}
+	})
+
+	t.Run("should allow reading paths outside the allow-list", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(JSONPatch, []byte(`[
+			{"op": "test", "path": "/ownerId", "value": "42"},
+			{"op": "copy", "from": "/address/street", "path": "/name"}
+		]`), &target)
+
+		require.NoError(t, err)
+		require.Equal(t, "Main", target.Name)
+	})
+
+	t.Run("should check every path changed by a merge patch", func(t *testing.T) {
+		target := newPet()
+		err := patcher.Apply(MergePatch, []byte(`{"name": "Max", "address": {"city": "Porto"}}`), &target)
+		require.NoError(t, err)
+		require.Equal(t, "Porto", target.Address.City)
+
+		err = patcher.Apply(MergePatch, []byte(`{"address": {"street": "Other"}}`), &target)
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+		require.Equal(t, "/address/street", problem.From(err).Extensions["path"])
+
+		err = patcher.Apply(MergePatch, []byte(`{"address": null}`), &target)
+		require.ErrorIs(t, err, ErrPathNotAllowed)
+	})
+}
+
+func TestPatcher_UnsupportedMediaType(t *testing.T) {
+	target := newPet()
+	err := New().Apply("application/json", []byte(`{"name": "Max"}`), &target)
+
+	require.ErrorIs(t, err, ErrUnsupportedMediaType)
+	require.Equal(t, http.StatusUnsupportedMediaType, problem.From(err).Status)
+	require.Equal(t, AcceptPatch, problem.From(err).Extensions["accept"])
+}
+
+func TestParsePointer(t *testing.T) {
+	tokens, err := parsePointer("/a~1b/m~0n/0")
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/b", "m~n", "0"}, tokens)
+	require.Equal(t, "/a~1b/m~0n/0", formatPointer(tokens))
+}
@@ -0,0 +1,183 @@
+package patch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePointer splits an RFC 6901 JSON pointer, such as /address/street, into its unescaped tokens.
+// The empty pointer refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// formatPointer joins the tokens back into a JSON pointer, escaping them.
+func formatPointer(tokens []string) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteByte('/')
+		builder.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return builder.String()
+}
+
+// get returns the value the tokens refer to.
+func get(node any, tokens []string) (any, error) {
+	for _, token := range tokens {
+		child, err := childOf(node, token)
+		if err != nil {
+			return nil, err
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// update applies fn to the container holding the last token and returns the updated document.
+// Slices are copied on change, so the updated containers are stored back into their parents.
+func update(node any, tokens []string, fn func(container any, key string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+
+	child, err := childOf(node, tokens[0])
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := update(child, tokens[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch container := node.(type) {
+	case map[string]any:
+		container[tokens[0]] = updated
+	case []any:
+		index, _ := strconv.Atoi(tokens[0])
+		container[index] = updated
+	}
+	return node, nil
+}
+
+func childOf(node any, token string) (any, error) {
+	switch container := node.(type) {
+	case map[string]any:
+		child, ok := container[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		return child, nil
+	case []any:
+		index, err := arrayIndex(token, len(container))
+		if err != nil {
+			return nil, err
+		}
+		return container[index], nil
+	default:
+		return nil, fmt.Errorf("cannot read %q of a scalar value", token)
+	}
+}
+
+func arrayIndex(token string, length int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if index >= length {
+		return 0, fmt.Errorf("array index %d out of bounds", index)
+	}
+	return index, nil
+}
+
+func addTo(value any) func(container any, key string) (any, error) {
+	return func(node any, key string) (any, error) {
+		switch container := node.(type) {
+		case map[string]any:
+			container[key] = value
+			return container, nil
+		case []any:
+			if key == "-" {
+				return append(container, value), nil
+			}
+
+			index, err := arrayIndex(key, len(container)+1)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make([]any, 0, len(container)+1)
+			result = append(result, container[:index]...)
+			result = append(result, value)
+			return append(result, container[index:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to a scalar value", key)
+		}
+	}
+}
+
+func removeFrom(node any, key string) (any, error) {
+	switch container := node.(type) {
+	case map[string]any:
+		if _, ok := container[key]; !ok {
+			return nil, fmt.Errorf("member %q not found", key)
+		}
+		delete(container, key)
+		return container, nil
+	case []any:
+		index, err := arrayIndex(key, len(container))
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]any, 0, len(container)-1)
+		result = append(result, container[:index]...)
+		return append(result, container[index+1:]...), nil
+	default:
+		return nil, fmt.Errorf("cannot remove %q from a scalar value", key)
+	}
+}
+
+func replaceIn(value any) func(container any, key string) (any, error) {
+	return func(node any, key string) (any, error) {
+		if _, err := childOf(node, key); err != nil {
+			return nil, err
+		}
+
+		switch container := node.(type) {
+		case map[string]any:
+			container[key] = value
+		case []any:
+			index, _ := strconv.Atoi(key)
+			container[index] = value
+		}
+		return node, nil
+	}
+}
+
+// hasPrefix reports whether the path tokens start with the prefix, where a * token of the prefix
+// matches any token.
+func hasPrefix(tokens []string, prefix []string) bool {
+	if len(prefix) > len(tokens) {
+		return false
+	}
+	for i, token := range prefix {
+		if token != "*" && token != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/softwareplace/goserve/env"
+)
+
+const (
+	ContentType = "application/problem+json"
+
+	// DefaultTypeBaseURI prefixes the type of the typed problems when PROBLEM_TYPE_BASE_URI is not set.
+	DefaultTypeBaseURI = "urn:goserve:problem:"
+)
+
+// Problem is an RFC 7807 problem details object. It implements error, so handlers can return it,
+// or panic with it, and the server answers it as `application/problem+json`.
+//
+// Example usage:
+//
+//	panic(problem.NotFound("pet 42 does not exist").With("petId", 42))
+type Problem struct {
+	Type       string         // Type is a URI reference identifying the problem type.
+	Title      string         // Title is a short, human-readable summary of the problem type.
+	Status     int            // Status is the HTTP status code.
+	Detail     string         // Detail is a human-readable explanation specific to this occurrence.
+	Instance   string         // Instance identifies this occurrence. The server sets it to the request session id.
+	Extensions map[string]any // Extensions are additional members, serialized next to the standard ones.
+	cause      error
+}
+
+// FieldError describes an invalid field of a Validation problem.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New creates a problem with the given status, type slug and detail. The type is the slug
+// prefixed with the PROBLEM_TYPE_BASE_URI, or `about:blank` if the slug is empty, and the
+// title is the status text.
+//
+// Parameters:
+//   - status: The HTTP status code.
+//   - slug: The problem type slug, such as `out-of-stock`.
+//   - detail: The explanation of this occurrence, safe to be shown to the client.
+func New(status int, slug string, detail string) *Problem {
+	problemType := "about:blank"
+	if slug != "" {
+		problemType = TypeURI(slug)
+	}
+
+	return &Problem{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// TypeURI returns the problem type URI of the slug, prefixed with the PROBLEM_TYPE_BASE_URI.
+func TypeURI(slug string) string {
+	return env.GetEnvOrDefault("PROBLEM_TYPE_BASE_URI", DefaultTypeBaseURI) + slug
+}
+
+func BadRequest(detail string) *Problem {
+	return New(http.StatusBadRequest, "bad-request", detail)
+}
+
+func Unauthorized(detail string) *Problem {
+	return New(http.StatusUnauthorized, "unauthorized", detail)
+}
+
+func Forbidden(detail string) *Problem {
+	return New(http.StatusForbidden, "forbidden", detail)
+}
+
+func NotFound(detail string) *Problem {
+	return New(http.StatusNotFound, "not-found", detail)
+}
+
+func Conflict(detail string) *Problem {
+	return New(http.StatusConflict, "conflict", detail)
+}
+
+func PreconditionFailed(detail string) *Problem {
+	return New(http.StatusPreconditionFailed, "precondition-failed", detail)
+}
+
+func TooManyRequests(detail string) *Problem {
+	return New(http.StatusTooManyRequests, "too-many-requests", detail)
+}
+
+func InternalServerError(detail string) *Problem {
+	return New(http.StatusInternalServerError, "internal-server-error", detail)
+}
+
+// Validation creates a 422 problem listing the invalid fields in the `errors` extension member.
+func Validation(detail string, fields ...FieldError) *Problem {
+	return New(http.StatusUnprocessableEntity, "validation", detail).With("errors", fields)
+}
+
+// With sets an extension member and returns the problem, for chaining.
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Wrap records the error that caused the problem, so it is logged and matched by errors.Is and
+// errors.As, without exposing it to the client.
+func (p *Problem) Wrap(err error) *Problem {
+	p.cause = err
+	return p
+}
+
+func (p *Problem) Error() string {
+	message := p.Title
+	if p.Detail != "" {
+		message += ": " + p.Detail
+	}
+	if p.cause != nil {
+		message += ": " + p.cause.Error()
+	}
+	return message
+}
+
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// Message returns the detail of the problem, or its title if there is no detail.
+func (p *Problem) Message() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// Provider is implemented by typed errors that know how to describe themselves as a Problem,
+// such as validator.ValidationErrors.
+type Provider interface {
+	Problem() *Problem
+}
+
+// From returns the Problem carried by err, either directly or through a Provider. Any other
+// error becomes a 500 problem without detail, so that internal error messages are not exposed
+// to the client.
+func From(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		copied := *p
+		return &copied
+	}
+
+	var provider Provider
+	if errors.As(err, &provider) {
+		return provider.Problem()
+	}
+	return InternalServerError("Failed to handle the request").Wrap(err)
+}
+
+// Is reports whether err carries a Problem, either directly or through a Provider.
+func Is(err error) bool {
+	var p *Problem
+	var provider Provider
+	return errors.As(err, &p) || errors.As(err, &provider)
+}
+
+// Write sends the problem as `application/problem+json`.
+func Write(w http.ResponseWriter, p *Problem) error {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,73 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem(t *testing.T) {
+	t.Run("should serialize the extension members next to the standard ones", func(t *testing.T) {
+		p := NotFound("pet 42 does not exist").With("petId", 42)
+		p.Instance = "session-id"
+
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"type": "urn:goserve:problem:not-found",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "pet 42 does not exist",
+			"instance": "session-id",
+			"petId": 42
+		}`, string(data))
+	})
+
+	t.Run("should keep wrapped problems reachable", func(t *testing.T) {
+		cause := errors.New("duplicated key")
+		err := fmt.Errorf("saving pet: %w", Conflict("pet already exists").Wrap(cause))
+
+		require.True(t, Is(err))
+		require.ErrorIs(t, err, cause)
+		require.Equal(t, http.StatusConflict, From(err).Status)
+	})
+
+	t.Run("should hide the message of untyped errors", func(t *testing.T) {
+		p := From(errors.New("connection refused to db:5432"))
+		require.Equal(t, http.StatusInternalServerError, p.Status)
+		require.NotContains(t, p.Detail, "db:5432")
+	})
+
+	t.Run("should list the invalid fields of a validation problem", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		require.NoError(t, Write(rr, Validation("invalid pet", FieldError{Field: "name", Message: "name is required"})))
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		require.Equal(t, ContentType, rr.Header().Get("Content-Type"))
+		require.Contains(t, rr.Body.String(), `"errors":[{"field":"name","message":"name is required"}]`)
+	})
+
+	t.Run("should use the configured type base URI", func(t *testing.T) {
+		t.Setenv("PROBLEM_TYPE_BASE_URI", "https://api.example.com/problems/")
+		require.Equal(t, "https://api.example.com/problems/conflict", Conflict("").Type)
+		require.Equal(t, "about:blank", New(http.StatusTeapot, "", "").Type)
+	})
+}
+
+type providerError struct{}
+
+func (providerError) Error() string { return "invalid input" }
+
+func (providerError) Problem() *Problem { return BadRequest("invalid input") }
+
+func TestFrom_Provider(t *testing.T) {
+	err := fmt.Errorf("binding: %w", providerError{})
+
+	require.True(t, Is(err))
+	require.Equal(t, http.StatusBadRequest, From(err).Status)
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Operator compares a field with the value of a filter Condition.
+type Operator string
+
+const (
+	Eq         Operator = "eq" // Eq matches values equal to the condition value.
+	Ne         Operator = "ne" // Ne matches values different from the condition value.
+	Lt         Operator = "lt" // Lt matches values lower than the condition value.
+	Le         Operator = "le" // Le matches values lower than or equal to the condition value.
+	Gt         Operator = "gt" // Gt matches values greater than the condition value.
+	Ge         Operator = "ge" // Ge matches values greater than or equal to the condition value.
+	In         Operator = "in" // In matches any of the comma separated values, such as `status in available,pending`.
+	Contains   Operator = "co" // Contains matches values containing the condition value.
+	StartsWith Operator = "sw" // StartsWith matches values starting with the condition value.
+)
+
+var operators = []Operator{Eq, Ne, Lt, Le, Gt, Ge, In, Contains, StartsWith}
+
+// Condition is a single comparison of a filter expression, such as `price lt 10`.
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+// Values returns the comma separated values of an In condition, or the single value otherwise.
+func (c Condition) Values() []string {
+	if c.Operator != In {
+		return []string{c.Value}
+	}
+
+	values := strings.Split(c.Value, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// ParseFilter parses a filter expression made of conditions joined by `and`, such as
+// `status eq available and price lt 10`. Values containing spaces are quoted with single
+// quotes, and a quote inside a quoted value is written twice, such as `name eq 'Bob''s dog'`.
+//
+// Parameters:
+//   - expression: The filter expression.
+//   - allowed: The fields that can be filtered.
+//
+// Returns:
+//   - []Condition: The conditions of the expression, all of which must match.
+//   - error: An *Error if the expression is malformed or uses a field that is not whitelisted.
+func ParseFilter(expression string, allowed []string) ([]Condition, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []Condition
+	for len(tokens) > 0 {
+		if len(tokens) < 3 {
+			return nil, filterError("expected <field> <operator> <value> but found %q", strings.Join(tokens, " "))
+		}
+
+		field, operator, value := tokens[0], Operator(strings.ToLower(tokens[1])), tokens[2]
+		if !slices.Contains(allowed, field) {
+			return nil, filterError("field %q is not filterable", field)
+		}
+		if !slices.Contains(operators, operator) {
+			return nil, filterError("operator %q is not supported", tokens[1])
+		}
+
+		conditions = append(conditions, Condition{Field: field, Operator: operator, Value: value})
+		tokens = tokens[3:]
+
+		if len(tokens) > 0 {
+			if !strings.EqualFold(tokens[0], "and") {
+				return nil, filterError("expected and but found %q", tokens[0])
+			}
+			tokens = tokens[1:]
+			if len(tokens) == 0 {
+				return nil, filterError("expected a condition after and")
+			}
+		}
+	}
+	return conditions, nil
+}
+
+func tokenize(expression string) ([]string, error) {
+	var tokens []string
+	var token strings.Builder
+	inQuotes, quoted := false, false
+
+	runes := []rune(expression)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' && inQuotes && i+1 < len(runes) && runes[i+1] == '\'':
+			token.WriteRune(r)
+			i++
+		case r == '\'':
+			inQuotes = !inQuotes
+			quoted = true
+		case r == ' ' && !inQuotes:
+			if token.Len() > 0 || quoted {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				quoted = false
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+
+	if inQuotes {
+		return nil, filterError("unterminated quoted value")
+	}
+	if token.Len() > 0 || quoted {
+		tokens = append(tokens, token.String())
+	}
+	return tokens, nil
+}
+
+func filterError(format string, args ...any) *Error {
+	return &Error{Parameter: FilterParam, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,161 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Envelope is implemented by Page, so that responses can set the Link and X-Total-Count
+// headers without knowing the item type.
+type Envelope interface {
+
+	// LinkHeader returns the RFC 8288 Link header value with the first, prev, next and last
+	// pages, relative to the request URL, or an empty string if there are no links.
+	LinkHeader(requestURL *url.URL) string
+
+	// TotalCount returns the total number of items, if known.
+	TotalCount() (int64, bool)
+}
+
+// Page is the standard envelope of list responses.
+type Page[E any] struct {
+	Items      []E    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	Size       int    `json:"size"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages int    `json:"totalPages,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPage creates the envelope of an offset paginated list.
+//
+// Parameters:
+//   - items: The items of the requested page.
+//   - total: The total number of items matching the query.
+//   - q: The query of the request.
+func NewPage[E any](items []E, total int64, q Query) Page[E] {
+	totalPages := 0
+	if q.Size > 0 {
+		totalPages = int((total + int64(q.Size) - 1) / int64(q.Size))
+	}
+
+	return Page[E]{
+		Items:      nonNil(items),
+		Page:       q.Page,
+		Size:       q.Size,
+		Total:      &total,
+		TotalPages: totalPages,
+	}
+}
+
+// NewCursorPage creates the envelope of a cursor paginated list.
+//
+// Parameters:
+//   - items: The items of the requested page.
+//   - nextCursor: The cursor of the next page, or an empty string on the last page. See EncodeCursor.
+//   - q: The query of the request.
+func NewCursorPage[E any](items []E, nextCursor string, q Query) Page[E] {
+	return Page[E]{
+		Items:      nonNil(items),
+		Size:       q.Size,
+		NextCursor: nextCursor,
+	}
+}
+
+// PageItems returns the items, enveloped as the data of the response when the server uses an envelope.
+func (p Page[E]) PageItems() any {
+	return p.Items
+}
+
+// PageMeta returns the pagination members, added to the meta of the response when the server uses
+// an envelope.
+func (p Page[E]) PageMeta() map[string]any {
+	meta := map[string]any{"size": p.Size}
+	if p.Page > 0 {
+		meta["page"] = p.Page
+	}
+	if p.Total != nil {
+		meta["total"] = *p.Total
+	}
+	if p.TotalPages > 0 {
+		meta["totalPages"] = p.TotalPages
+	}
+	if p.NextCursor != "" {
+		meta["nextCursor"] = p.NextCursor
+	}
+	return meta
+}
+
+func (p Page[E]) TotalCount() (int64, bool) {
+	if p.Total == nil {
+		return 0, false
+	}
+	return *p.Total, true
+}
+
+func (p Page[E]) LinkHeader(requestURL *url.URL) string {
+	var links []string
+	link := func(rel string, params map[string]string) {
+		target := *requestURL
+		values := target.Query()
+		for key, value := range params {
+			values.Set(key, value)
+		}
+		target.RawQuery = values.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.RequestURI(), rel))
+	}
+
+	if p.Total == nil {
+		if p.NextCursor != "" {
+			link("next", map[string]string{CursorParam: p.NextCursor})
+		}
+		return strings.Join(links, ", ")
+	}
+
+	lastPage := max(p.TotalPages, 1)
+	link("first", map[string]string{PageParam: "1"})
+	if p.Page > 1 {
+		link("prev", map[string]string{PageParam: strconv.Itoa(min(p.Page-1, lastPage))})
+	}
+	if p.Page < lastPage {
+		link("next", map[string]string{PageParam: strconv.Itoa(p.Page + 1)})
+	}
+	link("last", map[string]string{PageParam: strconv.Itoa(lastPage)})
+	return strings.Join(links, ", ")
+}
+
+// EncodeCursor encodes the position of the last item of a page, such as its sort keys, into an
+// opaque cursor.
+func EncodeCursor(position any) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor into position.
+//
+// Returns:
+//   - error: An *Error if the cursor is malformed.
+func DecodeCursor(cursor string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, position)
+	}
+	if err != nil {
+		return &Error{Parameter: CursorParam, Message: "malformed cursor"}
+	}
+	return nil
+}
+
+func nonNil[E any](items []E) []E {
+	if items == nil {
+		return []E{}
+	}
+	return items
+}
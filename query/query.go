@@ -0,0 +1,190 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+const (
+	PageParam   = "page"
+	SizeParam   = "size"
+	CursorParam = "cursor"
+	SortParam   = "sort"
+	FilterParam = "filter"
+
+	DefaultSize = 20
+	MaxSize     = 100
+)
+
+// Direction is the order of a sort field.
+type Direction string
+
+const (
+	Asc  Direction = "asc"
+	Desc Direction = "desc"
+)
+
+// Sort is a field of a multi-field sort, such as `?sort=name,-price`.
+type Sort struct {
+	Field     string
+	Direction Direction
+}
+
+// Options whitelists the fields a list endpoint can be sorted and filtered by, and sets its page size limits.
+type Options struct {
+	SortFields   []string // SortFields are the fields accepted by the sort parameter.
+	FilterFields []string // FilterFields are the fields accepted by the filter parameter.
+	DefaultSort  []Sort   // DefaultSort is used when the request has no sort parameter.
+	DefaultSize  int      // DefaultSize is the page size when the request has no size parameter. Default DefaultSize.
+	MaxSize      int      // MaxSize is the largest page size accepted. Default MaxSize.
+}
+
+// Query is the pagination, sort and filter of a list request.
+type Query struct {
+	Page   int         // Page is the 1-based page number of offset pagination.
+	Size   int         // Size is the page size.
+	Cursor string      // Cursor is the opaque position of cursor pagination. When set, Page is ignored.
+	Sort   []Sort      // Sort lists the sort fields, in order of precedence.
+	Filter []Condition // Filter lists the conditions that every item must match.
+}
+
+// Error reports an invalid query parameter.
+type Error struct {
+	Parameter string `json:"parameter"`
+	Message   string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s parameter: %s", e.Parameter, e.Message)
+}
+
+// Problem answers the error as a 400 Bad Request problem, with the invalid parameter.
+func (e *Error) Problem() *problem.Problem {
+	return problem.New(http.StatusBadRequest, "invalid-query", e.Error()).
+		With("parameter", e.Parameter)
+}
+
+// Offset returns the number of items to skip for offset pagination.
+func (q Query) Offset() int {
+	if q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Size
+}
+
+// IsCursor reports whether the request uses cursor pagination.
+func (q Query) IsCursor() bool {
+	return q.Cursor != ""
+}
+
+// Condition returns the first filter condition on the given field.
+func (q Query) Condition(field string) (Condition, bool) {
+	for _, condition := range q.Filter {
+		if condition.Field == field {
+			return condition, true
+		}
+	}
+	return Condition{}, false
+}
+
+// Parse reads the pagination, sort and filter parameters of a list request.
+//
+// The supported parameters are:
+//   - page and size: offset pagination, such as `?page=2&size=20`.
+//   - cursor and size: cursor pagination, such as `?cursor=eyJpZCI6NDJ9&size=20`.
+//   - sort: comma separated fields, descending when prefixed with `-`, such as `?sort=name,-price`.
+//   - filter: conditions joined by `and`, such as `?filter=status eq available and price lt 10`.
+//     See Operator for the supported operators. Values with spaces are quoted, such as `name eq 'hot dog'`.
+//
+// Parameters:
+//   - values: The query values of the request.
+//   - options: The whitelisted fields and page size limits.
+//
+// Returns:
+//   - Query: The parsed query.
+//   - error: An *Error if a parameter is invalid or uses a field that is not whitelisted.
+func Parse(values url.Values, options Options) (Query, error) {
+	options = options.withDefaults()
+	q := Query{Page: 1, Size: options.DefaultSize, Cursor: values.Get(CursorParam)}
+
+	if value := values.Get(PageParam); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return q, &Error{Parameter: PageParam, Message: "must be a positive integer"}
+		}
+		q.Page = page
+	}
+
+	if value := values.Get(SizeParam); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 || size > options.MaxSize {
+			return q, &Error{Parameter: SizeParam, Message: fmt.Sprintf("must be between 1 and %d", options.MaxSize)}
+		}
+		q.Size = size
+	}
+
+	// A page whose offset does not fit in an int would skip a negative number of items.
+	if maxPage := math.MaxInt/q.Size + 1; q.Page > maxPage {
+		return q, &Error{Parameter: PageParam, Message: fmt.Sprintf("must be at most %d", maxPage)}
+	}
+
+	sort, err := parseSort(values[SortParam], options.SortFields)
+	if err != nil {
+		return q, err
+	}
+	q.Sort = sort
+	if len(q.Sort) == 0 {
+		q.Sort = options.DefaultSort
+	}
+
+	for _, value := range values[FilterParam] {
+		conditions, err := ParseFilter(value, options.FilterFields)
+		if err != nil {
+			return q, err
+		}
+		q.Filter = append(q.Filter, conditions...)
+	}
+	return q, nil
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxSize <= 0 {
+		o.MaxSize = MaxSize
+	}
+	if o.DefaultSize <= 0 {
+		o.DefaultSize = min(DefaultSize, o.MaxSize)
+	}
+	return o
+}
+
+func parseSort(values []string, allowed []string) ([]Sort, error) {
+	var sort []Sort
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			direction := Asc
+			if name, found := strings.CutPrefix(field, "-"); found {
+				field, direction = name, Desc
+			} else {
+				field = strings.TrimPrefix(field, "+")
+			}
+
+			if !slices.Contains(allowed, field) {
+				return nil, &Error{Parameter: SortParam, Message: fmt.Sprintf("field %q is not sortable", field)}
+			}
+			sort = append(sort, Sort{Field: field, Direction: direction})
+		}
+	}
+	return sort, nil
+}
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{
+	SortFields:   []string{"name", "price"},
+	FilterFields: []string{"status", "price", "name"},
+	DefaultSort:  []Sort{{Field: "name", Direction: Asc}},
+	MaxSize:      50,
+}
+
+func parse(t *testing.T, rawQuery string) (Query, error) {
+	values, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	return Parse(values, testOptions)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should apply defaults", func(t *testing.T) {
+		q, err := parse(t, "")
+		require.NoError(t, err)
+		require.Equal(t, 1, q.Page)
+		require.Equal(t, DefaultSize, q.Size)
+		require.Equal(t, 0, q.Offset())
+		require.Equal(t, testOptions.DefaultSort, q.Sort)
+	})
+
+	t.Run("should parse pagination and multi-field sort", func(t *testing.T) {
+		q, err := parse(t, "page=3&size=10&sort=-price,name")
+		require.NoError(t, err)
+		require.Equal(t, 20, q.Offset())
+		require.Equal(t, []Sort{{"price", Desc}, {"name", Asc}}, q.Sort)
+	})
+
+	t.Run("should parse the filter expression", func(t *testing.T) {
+		q, err := parse(t, url.Values{FilterParam: {"status in available,pending and price lt 10 and name eq 'Bob''s dog'"}}.Encode())
+		require.NoError(t, err)
+		require.Equal(t, []Condition{
+			{Field: "status", Operator: In, Value: "available,pending"},
+			{Field: "price", Operator: Lt, Value: "10"},
+			{Field: "name", Operator: Eq, Value: "Bob's dog"},
+		}, q.Filter)
+		require.Equal(t, []string{"available", "pending"}, q.Filter[0].Values())
+
+		condition, ok := q.Condition("price")
+		require.True(t, ok)
+		require.Equal(t, "10", condition.Value)
+	})
+
+	t.Run("should accept the last page whose offset fits in an int", func(t *testing.T) {
+		q, err := parse(t, "page=461168601842738791&size=20")
+		require.NoError(t, err)
+		require.Equal(t, math.MaxInt-7, q.Offset())
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		invalid := map[string]string{
+			"page=0":                           PageParam,
+			"page=9223372036854775807":         PageParam,
+			"page=461168601842738792&size=20":  PageParam,
+			"size=51":                          SizeParam,
+			"sort=owner":                       SortParam,
+			"filter=owner+eq+bob":              FilterParam,
+			"filter=status+like+a":             FilterParam,
+			"filter=status+eq":                 FilterParam,
+			"filter=status+eq+a+or+price+lt+1": FilterParam,
+			"filter=status+eq+a+and":           FilterParam,
+			"filter=name+eq+%27unterminated":   FilterParam,
+		}
+
+		for rawQuery, parameter := range invalid {
+			_, err := parse(t, rawQuery)
+			var queryError *Error
+			require.ErrorAs(t, err, &queryError, rawQuery)
+			require.Equal(t, parameter, queryError.Parameter, rawQuery)
+		}
+	})
+}
+
+func TestPage(t *testing.T) {
+	requestURL, _ := url.Parse("/pets?size=10&page=2&sort=name")
+
+	t.Run("should link offset pages", func(t *testing.T) {
+		page := NewPage([]string{"a"}, 35, Query{Page: 2, Size: 10})
+		require.Equal(t, 4, page.TotalPages)
+
+		total, ok := page.TotalCount()
+		require.True(t, ok)
+		require.Equal(t, int64(35), total)
+		require.Equal(t,
+			`</pets?page=1&size=10&sort=name>; rel="first", `+
+				`</pets?page=1&size=10&sort=name>; rel="prev", `+
+				`</pets?page=3&size=10&sort=name>; rel="next", `+
+				`</pets?page=4&size=10&sort=name>; rel="last"`,
+			page.LinkHeader(requestURL),
+		)
+	})
+
+	t.Run("should link the next cursor", func(t *testing.T) {
+		cursor, err := EncodeCursor(map[string]int{"id": 42})
+		require.NoError(t, err)
+
+		page := NewCursorPage[string](nil, cursor, Query{Size: 10})
+		require.NotNil(t, page.Items)
+
+		_, ok := page.TotalCount()
+		require.False(t, ok)
+		require.Contains(t, page.LinkHeader(requestURL), "cursor="+cursor)
+
+		var position map[string]int
+		require.NoError(t, DecodeCursor(cursor, &position))
+		require.Equal(t, 42, position["id"])
+		require.Error(t, DecodeCursor("%%%", &position))
+	})
+}
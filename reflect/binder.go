@@ -0,0 +1,418 @@
+package reflect
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Binding sources, also used as the struct tags naming the parameter of a field.
+const (
+	SourceQuery  = "query"
+	SourceHeader = "header"
+	SourcePath   = "path"
+	SourceCookie = "cookie"
+	SourceForm   = "form"
+
+	// DefaultTag holds the value of a field when its parameter is missing, such as `default:"10"`.
+	DefaultTag = "default"
+)
+
+var bindingTags = []string{SourceQuery, SourceHeader, SourcePath, SourceCookie, SourceForm}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Sources holds the parameters of a request that Bind reads from.
+type Sources struct {
+	Query   url.Values        // Query holds the query parameters, read by `query` tags.
+	Header  http.Header       // Header holds the request headers, read by `header` tags.
+	Path    map[string]string // Path holds the route variables, read by `path` tags.
+	Cookies []*http.Cookie    // Cookies holds the request cookies, read by `cookie` tags.
+	Form    url.Values        // Form holds the form values, read by `form` tags.
+}
+
+// BindError reports a parameter whose value cannot be converted into the type of its field.
+type BindError struct {
+	Field  string // Field is the name of the parameter, such as page or X-Request-Id.
+	Source string // Source is where the parameter came from: query, header, path, cookie or form.
+	Value  string // Value is the raw value received.
+	Type   string // Type is the type of the field, such as int or time.Duration.
+	Err    error  // Err is the conversion error.
+}
+
+// Error implements the error interface
+func (e *BindError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("parameter %s has an invalid value %q: expected %s", e.Field, e.Value, e.Type)
+	}
+	return fmt.Sprintf("%s parameter %s has an invalid value %q: expected %s", e.Source, e.Field, e.Value, e.Type)
+}
+
+// Unwrap returns the conversion error.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// BindErrors lists every parameter Bind could not convert.
+type BindErrors []*BindError
+
+// Error implements the error interface
+func (e BindErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// HasBindingTags reports whether the struct type, or one of its nested structs, declares a
+// query, header, path, cookie or form tag.
+func HasBindingTags(t reflect.Type) bool {
+	plan := planOf(t)
+	return plan != nil && plan.hasTags
+}
+
+// HasBindingSource reports whether the struct type, or one of its nested structs, declares a field
+// bound from the source, such as SourceForm.
+func HasBindingSource(t reflect.Type, source string) bool {
+	plan := planOf(t)
+	if plan == nil {
+		return false
+	}
+
+	for _, fp := range plan.bound {
+		if fp.source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Bind sets the fields of the target struct declaring a `query:"name"`, `header:"name"`,
+// `path:"name"`, `cookie:"name"` or `form:"name"` tag from that source only, so a header and a
+// query parameter with the same name never collide. Missing parameters take the value of the
+// `default` tag, if any. Untagged nested structs are bound recursively.
+//
+// Besides strings, numbers and booleans, fields may be time.Time (RFC 3339 or 2006-01-02),
+// time.Duration, encoding.TextUnmarshaler types such as uuid.UUID, pointers, slices of repeated
+// parameters, maps of `name[key]=value` query or form parameters, and structs encoded as JSON.
+//
+// Parameters:
+//   - target: A pointer to the struct to bind.
+//   - sources: The parameters of the request.
+//
+// Returns:
+//   - error: nil on success, or BindErrors listing every parameter that could not be converted,
+//     with its source. The fields of the other parameters are still set.
+//
+// Example usage:
+//
+//	type ListPetsRequest struct {
+//		Page    int               `query:"page" default:"1"`
+//		Timeout time.Duration     `header:"X-Timeout" default:"5s"`
+//		OwnerId uuid.UUID         `path:"ownerId"`
+//		Filter  map[string]string `query:"filter"` // ?filter[status]=available
+//	}
+//
+//	var request ListPetsRequest
+//	err := goservereflect.Bind(&request, goservereflect.Sources{Query: r.URL.Query(), Path: mux.Vars(r)})
+func Bind(target any, sources Sources) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("bind target must be a non nil pointer to struct, got %T", target)
+	}
+
+	value = value.Elem()
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to struct, got %T", target)
+	}
+
+	var errs BindErrors
+	bindStruct(value, sources, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func bindStruct(value reflect.Value, sources Sources, errs *BindErrors) {
+	for _, fp := range planOf(value.Type()).bound {
+		raw, found := sources.lookup(fp.source, fp.name, fp.isMap)
+		if !found {
+			if fp.defaults == nil {
+				continue
+			}
+			raw = fp.defaults
+		}
+
+		var keyed map[string][]string
+		if fp.isMap {
+			keyed = sources.keyed(fp.source, fp.name)
+		}
+
+		if err := setValue(fp.fieldOf(value), raw, keyed); err != nil {
+			*errs = append(*errs, &BindError{
+				Field:  fp.name,
+				Source: fp.source,
+				Value:  strings.Join(raw, ","),
+				Type:   fp.typeString,
+				Err:    err,
+			})
+		}
+	}
+}
+
+// isBindable reports whether the field can be set. Unexported fields are skipped, including
+// unexported embedded structs: embed an exported struct to bind its promoted fields.
+func isBindable(field reflect.StructField) bool {
+	return field.IsExported()
+}
+
+func bindingOf(field reflect.StructField) (string, string, bool) {
+	for _, source := range bindingTags {
+		if name, ok := field.Tag.Lookup(source); ok && name != "-" {
+			return source, strings.Split(name, ",")[0], true
+		}
+	}
+	return "", "", false
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func (s Sources) lookup(source string, name string, isMap bool) ([]string, bool) {
+	switch source {
+	case SourceQuery, SourceForm:
+		values := s.Query
+		if source == SourceForm {
+			values = s.Form
+		}
+		if raw, ok := values[name]; ok {
+			return raw, true
+		}
+		if isMap && len(s.keyed(source, name)) > 0 {
+			return nil, true
+		}
+	case SourceHeader:
+		if raw := s.Header.Values(name); len(raw) > 0 {
+			return raw, true
+		}
+	case SourcePath:
+		if raw, ok := s.Path[name]; ok {
+			return []string{raw}, true
+		}
+	case SourceCookie:
+		for _, cookie := range s.Cookies {
+			if cookie.Name == name {
+				return []string{cookie.Value}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// keyed returns the `name[key]=value` parameters of the query or form, by key.
+func (s Sources) keyed(source string, name string) map[string][]string {
+	values := s.Query
+	if source == SourceForm {
+		values = s.Form
+	} else if source != SourceQuery {
+		return nil
+	}
+
+	var keyed map[string][]string
+	prefix := name + "["
+	for key, raw := range values {
+		if strings.HasPrefix(key, prefix) && strings.HasSuffix(key, "]") {
+			if keyed == nil {
+				keyed = make(map[string][]string)
+			}
+			keyed[key[len(prefix):len(key)-1]] = raw
+		}
+	}
+	return keyed
+}
+
+func setValue(field reflect.Value, raw []string, keyed map[string][]string) error {
+	t := field.Type()
+
+	switch {
+	case t.Kind() == reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		if err := setValue(elem.Elem(), raw, keyed); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+
+	case t.Kind() == reflect.Map:
+		return setMap(field, raw, keyed)
+
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 && !isScalar(t):
+		slice := reflect.MakeSlice(t, len(raw), len(raw))
+		for i, item := range raw {
+			if err := setScalar(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	if len(raw) == 0 {
+		return nil
+	}
+	return setScalar(field, raw[0])
+}
+
+func setMap(field reflect.Value, raw []string, keyed map[string][]string) error {
+	t := field.Type()
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map key type %s", t.Key())
+	}
+
+	if len(keyed) == 0 && len(raw) > 0 {
+		// A map can also be sent as a JSON object.
+		target := reflect.New(t)
+		if err := json.Unmarshal([]byte(raw[0]), target.Interface()); err != nil {
+			return err
+		}
+		field.Set(target.Elem())
+		return nil
+	}
+
+	result := reflect.MakeMapWithSize(t, len(keyed))
+	for key, values := range keyed {
+		item := reflect.New(t.Elem()).Elem()
+		if err := setValue(item, values, nil); err != nil {
+			return err
+		}
+		result.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), item)
+	}
+	field.Set(result)
+	return nil
+}
+
+func setScalar(field reflect.Value, raw string) error {
+	t := field.Type()
+
+	if t.Kind() == reflect.Ptr {
+		elem := reflect.New(t.Elem())
+		if err := setScalar(elem.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	switch t {
+	case timeType:
+		parsed, err := parseTime(raw)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	case durationType:
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return json.Unmarshal([]byte(raw), field.Addr().Interface())
+		}
+		field.SetBytes([]byte(raw))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Struct, reflect.Array, reflect.Interface:
+		return json.Unmarshal([]byte(raw), field.Addr().Interface())
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+	return nil
+}
+
+func parseTime(raw string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
+
+// isScalar reports whether values of the type are bound from a single parameter value, such as
+// encoding.TextUnmarshaler slices like net.IP.
+func isScalar(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isNestedStruct reports whether the type is a struct, or pointer to struct, bound field by field.
+func isNestedStruct(t reflect.Type) bool {
+	t = indirectType(t)
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func allocate(field reflect.Value) reflect.Value {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	return field
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
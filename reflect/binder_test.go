@@ -0,0 +1,126 @@
+package reflect
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type Pagination struct {
+	Page int  `query:"page" default:"1"`
+	Size *int `query:"size"`
+}
+
+type bindRequest struct {
+	Pagination
+	Id        string                     `path:"id"`
+	QueryId   string                     `query:"id"`
+	HeaderId  string                     `header:"X-Id"`
+	Session   string                     `cookie:"session"`
+	Name      string                     `form:"name"`
+	Owner     uuid.UUID                  `path:"ownerId"`
+	Timeout   time.Duration              `header:"X-Timeout" default:"5s"`
+	Since     time.Time                  `query:"since"`
+	Verbose   *bool                      `query:"verbose"`
+	Tags      []string                   `query:"tag"`
+	Ids       []int64                    `query:"ids"`
+	Filter    map[string]string          `query:"filter"`
+	Limits    map[string]int             `query:"limits"`
+	Location  struct{ Lat, Lng float64 } `query:"location"`
+	Untouched string
+}
+
+func TestBind(t *testing.T) {
+	owner := uuid.New()
+	sources := Sources{
+		Query: url.Values{
+			"id":            {"query-id"},
+			"size":          {"20"},
+			"since":         {"2025-01-31"},
+			"verbose":       {"true"},
+			"tag":           {"a", "b"},
+			"ids":           {"1", "2"},
+			"filter[name]":  {"rex"},
+			"filter[state]": {"available"},
+			"limits":        {`{"max": 10}`},
+			"location":      {`{"Lat": 1.5, "Lng": -2}`},
+			"Untouched":     {"value"},
+		},
+		Header:  http.Header{"X-Id": {"header-id"}},
+		Path:    map[string]string{"id": "path-id", "ownerId": owner.String()},
+		Cookies: []*http.Cookie{{Name: "session", Value: "cookie-value"}},
+		Form:    url.Values{"name": {"form-name"}},
+	}
+
+	t.Run("should bind every field from its own source", func(t *testing.T) {
+		var request bindRequest
+		require.NoError(t, Bind(&request, sources))
+
+		require.Equal(t, 1, request.Page)
+		require.Equal(t, 20, *request.Size)
+		require.Equal(t, "path-id", request.Id)
+		require.Equal(t, "query-id", request.QueryId)
+		require.Equal(t, "header-id", request.HeaderId)
+		require.Equal(t, "cookie-value", request.Session)
+		require.Equal(t, "form-name", request.Name)
+		require.Equal(t, owner, request.Owner)
+		require.Equal(t, 5*time.Second, request.Timeout)
+		require.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), request.Since)
+		require.True(t, *request.Verbose)
+		require.Equal(t, []string{"a", "b"}, request.Tags)
+		require.Equal(t, []int64{1, 2}, request.Ids)
+		require.Equal(t, map[string]string{"name": "rex", "state": "available"}, request.Filter)
+		require.Equal(t, map[string]int{"max": 10}, request.Limits)
+		require.Equal(t, 1.5, request.Location.Lat)
+		require.Empty(t, request.Untouched)
+	})
+
+	t.Run("should report conversion failures with their source", func(t *testing.T) {
+		var request bindRequest
+		err := Bind(&request, Sources{
+			Query:  url.Values{"page": {"first"}, "ids": {"1", "x"}},
+			Header: http.Header{"X-Timeout": {"soon"}},
+			Path:   map[string]string{"ownerId": "not-a-uuid"},
+		})
+
+		var bindErrors BindErrors
+		require.ErrorAs(t, err, &bindErrors)
+		require.Len(t, bindErrors, 4)
+
+		bySource := map[string]string{}
+		for _, bindError := range bindErrors {
+			bySource[bindError.Source+":"+bindError.Field] = bindError.Type
+		}
+		require.Equal(t, map[string]string{
+			"query:page":       "int",
+			"query:ids":        "[]int64",
+			"header:X-Timeout": "time.Duration",
+			"path:ownerId":     "uuid.UUID",
+		}, bySource)
+		require.Contains(t, err.Error(), `query parameter page has an invalid value "first": expected int`)
+	})
+
+	t.Run("should reject values overflowing the field", func(t *testing.T) {
+		var request struct {
+			Small int8 `query:"small"`
+		}
+		require.Error(t, Bind(&request, Sources{Query: url.Values{"small": {"300"}}}))
+	})
+
+	t.Run("should find binding tags on nested structs", func(t *testing.T) {
+		require.True(t, HasBindingTags(typeOf[bindRequest]()))
+		require.True(t, HasBindingTags(typeOf[struct{ Page *Pagination }]()))
+		require.False(t, HasBindingTags(typeOf[struct {
+			Name string `json:"name"`
+		}]()))
+	})
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -12,6 +12,8 @@ import (
 )
 
 type ParamsExtractorSource struct {
+	// Name is the source of the parameters, such as query or header, reported by the conversion errors.
+	Name string
 	// Source is a flat map where keys are parameter names and values are their corresponding string values.
 	Source map[string]string
 	// Tree represents a hierarchical map, primarily for parameters with multiple values, such as query parameters.
@@ -21,55 +23,105 @@ type ParamsExtractorSource struct {
 // ParamsExtract extracts parameters from one or more ParamsExtractorSource instances,
 // converts them to their target types, and binds them to the specified target struct.
 // The function handles both single-value and multi-value parameters, supports JSON tags for field mapping,
-// and ensures type conversions using ConvertValue and ConvertValues methods.
+// and sets the fields directly, using a binding plan built once per struct type.
+// Parameters of later sources override those of earlier ones.
 //
-// target: The struct to which the extracted and converted parameters will be bound.
+// target: The pointer to the struct to which the extracted and converted parameters will be bound.
 // source: A variadic list of ParamsExtractorSource instances, each representing a source of parameters.
 //
-// Returns an error if parameter extraction or JSON unmarshalling into the target struct fails.
+// Returns BindErrors listing every parameter that could not be converted, with the Name of its source,
+// or nil. The fields of the other parameters are still set.
 func ParamsExtract(target interface{}, source ...ParamsExtractorSource) error {
-	targetType := reflect.TypeOf(target)
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("params target must be a non nil pointer to struct, got %T", target)
+	}
+
+	value = allocate(value.Elem())
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("params target must be a pointer to struct, got %T", target)
+	}
+
+	return extractInto(value, planOf(value.Type()).byName, source)
+}
+
+// ParamsExtractUntagged binds the parameters as ParamsExtract does, but only to the fields without a
+// query, header, path, cookie or form tag, leaving those to Bind.
+func ParamsExtractUntagged(target interface{}, source ...ParamsExtractorSource) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("params target must be a non nil pointer to struct, got %T", target)
+	}
 
-	jsonContent, err := ParseToJson(targetType, source...)
-	if err != nil {
-		return err
+	value = allocate(value.Elem())
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("params target must be a pointer to struct, got %T", target)
 	}
 
-	return json.Unmarshal(jsonContent, target)
+	return extractInto(value, planOf(value.Type()).untagged, source)
 }
 
+// FormBodyExtract binds the parameters, usually the values of a multipart form, to the Body field
+// of the target struct, as ParamsExtract does for the target itself.
 func FormBodyExtract(target interface{}, source ...ParamsExtractorSource) {
-	targetType := reflect.TypeOf(target).Elem()
-
-	for i := 0; i < targetType.NumField(); i++ {
-		field := targetType.Field(i)
-		if field.Name == "Body" {
-			err := json.Unmarshal([]byte(`{"body": {}}`), &target)
-			if err != nil {
-				break
-			}
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
 
-			fieldInstance := reflect.ValueOf(target).Elem().FieldByName("Body").Interface()
-			targetBodyType := reflect.TypeOf(fieldInstance)
+	plan := planOf(value.Type())
+	if plan == nil || plan.body == nil {
+		return
+	}
 
-			jsonContent, err := ParseToJson(targetBodyType, source...)
+	body := allocate(plan.body.fieldOf(allocate(value.Elem())))
+	if body.Kind() != reflect.Struct {
+		return
+	}
 
-			if err != nil {
-				log.Errorf("FormBodyExtract: %v", err)
-				break
-			}
+	if err := extractInto(body, planOf(body.Type()).byName, source); err != nil {
+		log.Errorf("FormBodyExtract: %v", err)
+	}
+}
 
-			err = json.Unmarshal([]byte(fmt.Sprintf(`{"body": %s}`, jsonContent)), &target)
+func extractInto(value reflect.Value, fields map[string]*fieldPlan, sources []ParamsExtractorSource) error {
+	var errs BindErrors
+	set := func(source string, name string, raw []string) {
+		fp, ok := lookup(fields, name)
+		if !ok {
+			return
+		}
 
-			if err != nil {
-				log.Errorf("FormBodyExtract: %v", err)
-			}
+		if err := setValue(fp.fieldOf(value), raw, nil); err != nil {
+			errs = append(errs, &BindError{
+				Field:  name,
+				Source: source,
+				Value:  strings.Join(raw, ","),
+				Type:   fp.typeString,
+				Err:    err,
+			})
+		}
+	}
 
-			break
+	for _, values := range sources {
+		for name, raw := range values.Tree {
+			set(values.Name, name, raw)
 		}
+
+		for name, raw := range values.Source {
+			set(values.Name, name, []string{raw})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
+	return nil
 }
 
+// ParseToJson converts the parameters matching the fields of the target type into a JSON object.
+//
+// Deprecated: ParamsExtract sets the fields directly and no longer uses it.
 func ParseToJson(
 	targetType reflect.Type,
 	source ...ParamsExtractorSource,
@@ -0,0 +1,160 @@
+package reflect
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type petBody struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// findPetsClientRequest mirrors the request structs generated from OpenAPI operations.
+type findPetsClientRequest struct {
+	OwnerId    int64    `name:"ownerId" path:"ownerId" required:"true" validate:"required" json:"ownerId"`
+	Tags       []string `name:"tags" query:"tags" json:"tags"`
+	Limit      int32    `name:"limit" query:"limit" json:"limit"`
+	Since      string   `name:"since" query:"since" json:"since"`
+	XRequestId string   `name:"X-Request-Id" header:"X-Request-Id" json:"X-Request-Id"`
+	Body       petBody  `name:"body" json:"body"`
+}
+
+// legacyRequest has no binding tags, so its fields match parameters by name.
+type legacyRequest struct {
+	XApiKey  string        `json:"X-Api-Key"`
+	Page     int           `json:"page"`
+	Ratio    float64       `json:"ratio"`
+	Enabled  bool          `json:"enabled"`
+	Ids      []int         `json:"ids"`
+	Since    time.Time     `json:"since"`
+	Timeout  time.Duration `json:"timeout"`
+	Body     petBody       `json:"body"`
+	Next     *legacyRequest
+	internal string
+}
+
+type pagination struct {
+	Page int `query:"page" json:"page"`
+}
+
+// embeddedUnexportedRequest embeds an unexported struct, which the binding skips.
+type embeddedUnexportedRequest struct {
+	pagination
+	Name string `json:"name"`
+}
+
+func TestParamsExtract(t *testing.T) {
+	t.Run("should match fields by name or json tag", func(t *testing.T) {
+		var request legacyRequest
+		err := ParamsExtract(&request,
+			ParamsExtractorSource{Tree: url.Values{
+				"PAGE":    {"2"},
+				"ratio":   {"0.5"},
+				"enabled": {"true"},
+				"ids":     {"1", "2"},
+				"since":   {"2025-01-31"},
+				"timeout": {"3s"},
+				"unknown": {"x"},
+			}},
+			ParamsExtractorSource{Tree: http.Header{"Xapikey": {"from-header"}}},
+			ParamsExtractorSource{Source: map[string]string{"page": "3"}},
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, "from-header", request.XApiKey)
+		require.Equal(t, 3, request.Page, "later sources override earlier ones")
+		require.Equal(t, 0.5, request.Ratio)
+		require.True(t, request.Enabled)
+		require.Equal(t, []int{1, 2}, request.Ids)
+		require.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), request.Since)
+		require.Equal(t, 3*time.Second, request.Timeout)
+	})
+
+	t.Run("should strip dashes from parameter names", func(t *testing.T) {
+		type request struct {
+			RequestId string
+		}
+
+		var target request
+		require.NoError(t, ParamsExtract(&target, ParamsExtractorSource{Tree: http.Header{"Request-Id": {"42"}}}))
+		require.Equal(t, "42", target.RequestId)
+	})
+
+	t.Run("should keep the other fields when a conversion fails", func(t *testing.T) {
+		var request legacyRequest
+		err := ParamsExtract(&request, ParamsExtractorSource{Source: map[string]string{"page": "first", "ratio": "2"}})
+
+		require.ErrorContains(t, err, "page")
+		require.Zero(t, request.Page)
+		require.Equal(t, 2.0, request.Ratio)
+	})
+
+	t.Run("should skip the unexported embedded structs", func(t *testing.T) {
+		var request embeddedUnexportedRequest
+		require.False(t, HasBindingTags(reflect.TypeOf(request)))
+
+		require.NoError(t, ParamsExtract(&request, ParamsExtractorSource{Source: map[string]string{"page": "3", "name": "Rex"}}))
+		require.Zero(t, request.Page)
+		require.Equal(t, "Rex", request.Name)
+	})
+
+	t.Run("should fill the body from form values", func(t *testing.T) {
+		var request findPetsClientRequest
+		FormBodyExtract(&request, ParamsExtractorSource{Tree: url.Values{"name": {"Rex"}, "tags": {"a", "b"}}})
+
+		require.Equal(t, petBody{Name: "Rex", Tags: []string{"a", "b"}}, request.Body)
+		require.Empty(t, request.Tags)
+	})
+}
+
+var benchmarkSources = []ParamsExtractorSource{
+	{Tree: url.Values{"tags": {"dog", "cat"}, "limit": {"20"}, "since": {"2025-01-31"}}},
+	{Tree: http.Header{
+		"X-Request-Id":    {"5b7b8e52"},
+		"Accept":          {"application/json"},
+		"Accept-Encoding": {"gzip"},
+		"User-Agent":      {"goserve-benchmark"},
+	}},
+	{Source: map[string]string{"ownerId": "42"}},
+}
+
+func BenchmarkParamsExtract(b *testing.B) {
+	b.Run("binding plan", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var request findPetsClientRequest
+			_ = ParamsExtract(&request, benchmarkSources...)
+		}
+	})
+
+	b.Run("json detour", func(b *testing.B) {
+		requestType := typeOf[findPetsClientRequest]()
+		b.ReportAllocs()
+		for b.Loop() {
+			var request findPetsClientRequest
+			jsonContent, _ := ParseToJson(requestType, benchmarkSources...)
+			_ = json.Unmarshal(jsonContent, &request)
+		}
+	})
+}
+
+func BenchmarkBind(b *testing.B) {
+	sources := Sources{
+		Query:  url.Values{"tags": {"dog", "cat"}, "limit": {"20"}, "since": {"2025-01-31"}},
+		Header: http.Header{"X-Request-Id": {"5b7b8e52"}},
+		Path:   map[string]string{"ownerId": "42"},
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var request findPetsClientRequest
+		_ = Bind(&request, sources)
+	}
+}
@@ -0,0 +1,153 @@
+package reflect
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// typePlan is the binding plan of a struct type, built once by planOf and shared by every request.
+type typePlan struct {
+	byName   map[string]*fieldPlan // byName finds the field of a parameter by lower case field or json name.
+	untagged map[string]*fieldPlan // untagged is byName restricted to the fields without a binding tag.
+	bound    []*fieldPlan          // bound lists the fields declaring a binding tag, including those of nested structs.
+	body     *fieldPlan            // body is the Body field filled by FormBodyExtract, if any.
+	hasTags  bool
+}
+
+// fieldPlan holds what is needed to set a field without looking up its struct tags again.
+type fieldPlan struct {
+	index      []int
+	source     string
+	name       string
+	defaults   []string
+	isMap      bool
+	typeString string
+}
+
+var plans sync.Map // map[reflect.Type]*typePlan
+
+// planOf returns the cached binding plan of the struct type, or nil if t is not a struct.
+func planOf(t reflect.Type) *typePlan {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if plan, ok := plans.Load(t); ok {
+		return plan.(*typePlan)
+	}
+
+	plan := buildPlan(t)
+	actual, _ := plans.LoadOrStore(t, plan)
+	return actual.(*typePlan)
+}
+
+func buildPlan(t reflect.Type) *typePlan {
+	plan := &typePlan{
+		byName:   make(map[string]*fieldPlan, t.NumField()*2),
+		untagged: make(map[string]*fieldPlan, t.NumField()*2),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isBindable(field) {
+			continue
+		}
+
+		fp := newFieldPlan(field, []int{i})
+
+		// The first field matching a name wins, as FindField does.
+		for _, name := range []string{strings.ToLower(field.Name), strings.ToLower(jsonName(field))} {
+			if _, ok := plan.byName[name]; !ok {
+				plan.byName[name] = fp
+			}
+			if _, ok := plan.untagged[name]; !ok && fp.source == "" {
+				plan.untagged[name] = fp
+			}
+		}
+
+		if field.Name == "Body" {
+			plan.body = fp
+		}
+	}
+
+	plan.bound = boundFields(t, nil, map[reflect.Type]bool{})
+	plan.hasTags = len(plan.bound) > 0
+	return plan
+}
+
+// boundFields lists the fields of t declaring a binding tag, walking untagged nested structs.
+// visiting guards against recursive types, such as a struct holding a pointer to itself.
+func boundFields(t reflect.Type, prefix []int, visiting map[reflect.Type]bool) []*fieldPlan {
+	t = indirectType(t)
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var bound []*fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isBindable(field) {
+			continue
+		}
+
+		index := append(append([]int{}, prefix...), i)
+		if _, _, ok := bindingOf(field); ok {
+			bound = append(bound, newFieldPlan(field, index))
+			continue
+		}
+
+		if isNestedStruct(field.Type) {
+			bound = append(bound, boundFields(field.Type, index, visiting)...)
+		}
+	}
+	return bound
+}
+
+func newFieldPlan(field reflect.StructField, index []int) *fieldPlan {
+	fp := &fieldPlan{
+		index:      index,
+		isMap:      indirectType(field.Type).Kind() == reflect.Map,
+		typeString: field.Type.String(),
+	}
+
+	if source, name, ok := bindingOf(field); ok {
+		if name == "" {
+			name = jsonName(field)
+		}
+		fp.source, fp.name = source, name
+
+		if defaultValue, ok := field.Tag.Lookup(DefaultTag); ok {
+			fp.defaults = []string{defaultValue}
+		}
+	}
+	return fp
+}
+
+// lookup finds the field of a parameter in fields by its name, or by its name without dashes.
+func lookup(fields map[string]*fieldPlan, name string) (*fieldPlan, bool) {
+	name = strings.ToLower(name)
+	if fp, ok := fields[name]; ok {
+		return fp, true
+	}
+
+	if strings.Contains(name, "-") {
+		fp, ok := fields[strings.ReplaceAll(name, "-", "")]
+		return fp, ok
+	}
+	return nil, false
+}
+
+// fieldOf returns the field at the plan index, allocating the nil pointers to nested structs on the way.
+func (fp *fieldPlan) fieldOf(value reflect.Value) reflect.Value {
+	for i, index := range fp.index {
+		if i > 0 {
+			value = allocate(value)
+		}
+		value = value.Field(index)
+	}
+	return value
+}
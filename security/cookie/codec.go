@@ -0,0 +1,183 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidValue = errors.New("cookie value is invalid or was tampered with")
+	ErrExpiredValue = errors.New("cookie value has expired")
+)
+
+const separator = "|"
+
+// Codec encodes and decodes cookie values, so that the client cannot read or forge them.
+// The cookie name is bound to the value, so a value issued for one cookie is rejected
+// when presented as another one.
+type Codec interface {
+
+	// Encode protects the value of the cookie with the given name.
+	//
+	// Parameters:
+	//   - name: The cookie name.
+	//   - value: The plain value to protect.
+	//
+	// Returns:
+	//   - string: The protected value, safe to be used as a cookie value.
+	//   - error: An error if the value could not be protected.
+	Encode(name string, value string) (string, error)
+
+	// Decode verifies and returns the plain value of the cookie with the given name.
+	//
+	// Parameters:
+	//   - name: The cookie name.
+	//   - value: The protected value, as read from the request.
+	//
+	// Returns:
+	//   - string: The plain value.
+	//   - error: ErrInvalidValue if the value was tampered with, or ErrExpiredValue if it is older than the max age.
+	Decode(name string, value string) (string, error)
+}
+
+type signedCodec struct {
+	key    []byte
+	maxAge time.Duration
+}
+
+type encryptedCodec struct {
+	aead   cipher.AEAD
+	maxAge time.Duration
+}
+
+// NewSigned creates a Codec that signs the values with HMAC-SHA256. The value stays readable
+// by the client, but any change to it is detected.
+//
+// Parameters:
+//   - secret: The secret used to derive the signing key, such as the API_SECRET_KEY.
+//   - maxAge: The maximum age of a value. Zero means that values never expire.
+func NewSigned(secret []byte, maxAge time.Duration) Codec {
+	return &signedCodec{
+		key:    deriveKey(secret, "goserve/cookie/sign"),
+		maxAge: maxAge,
+	}
+}
+
+// NewEncrypted creates a Codec that encrypts and authenticates the values with AES-256-GCM,
+// so the client can neither read nor change them.
+//
+// Parameters:
+//   - secret: The secret used to derive the encryption key, such as the API_SECRET_KEY.
+//   - maxAge: The maximum age of a value. Zero means that values never expire.
+func NewEncrypted(secret []byte, maxAge time.Duration) Codec {
+	block, err := aes.NewCipher(deriveKey(secret, "goserve/cookie/encrypt"))
+	if err != nil {
+		// A derived key always has a valid AES-256 size.
+		panic(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+
+	return &encryptedCodec{
+		aead:   aead,
+		maxAge: maxAge,
+	}
+}
+
+func (c *signedCodec) Encode(name string, value string) (string, error) {
+	payload := timestamped(value)
+	signature := c.sign(name, payload)
+	return payload + separator + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (c *signedCodec) Decode(name string, value string) (string, error) {
+	index := strings.LastIndex(value, separator)
+	if index < 0 {
+		return "", ErrInvalidValue
+	}
+
+	payload := value[:index]
+	signature, err := base64.RawURLEncoding.DecodeString(value[index+1:])
+	if err != nil || !hmac.Equal(signature, c.sign(name, payload)) {
+		return "", ErrInvalidValue
+	}
+
+	return fromTimestamped(payload, c.maxAge)
+}
+
+func (c *signedCodec) sign(name string, payload string) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(name))
+	mac.Write([]byte(separator))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func (c *encryptedCodec) Encode(name string, value string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(timestamped(value)), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (c *encryptedCodec) Decode(name string, value string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidValue
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	payload, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", ErrInvalidValue
+	}
+
+	return fromTimestamped(string(payload), c.maxAge)
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// timestamped prefixes the base64 encoded value with its creation time, so that the max age can be verified.
+func timestamped(value string) string {
+	return strconv.FormatInt(time.Now().Unix(), 10) + separator + base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func fromTimestamped(payload string, maxAge time.Duration) (string, error) {
+	created, encoded, found := strings.Cut(payload, separator)
+	if !found {
+		return "", ErrInvalidValue
+	}
+
+	createdAt, err := strconv.ParseInt(created, 10, 64)
+	if err != nil {
+		return "", ErrInvalidValue
+	}
+
+	if maxAge > 0 && time.Since(time.Unix(createdAt, 0)) > maxAge {
+		return "", ErrExpiredValue
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidValue
+	}
+	return string(value), nil
+}
@@ -0,0 +1,62 @@
+package cookie
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	secret := []byte("DlJeR4%pPbB5Pr5cICMxg0xB")
+
+	codecs := map[string]Codec{
+		"signed":    NewSigned(secret, time.Hour),
+		"encrypted": NewEncrypted(secret, time.Hour),
+	}
+
+	for name, codec := range codecs {
+		t.Run(name+" should decode the encoded value", func(t *testing.T) {
+			encoded, err := codec.Encode("theme", "dark")
+			require.NoError(t, err)
+
+			decoded, err := codec.Decode("theme", encoded)
+			require.NoError(t, err)
+			require.Equal(t, "dark", decoded)
+		})
+
+		t.Run(name+" should reject a value issued for another cookie", func(t *testing.T) {
+			encoded, err := codec.Encode("theme", "dark")
+			require.NoError(t, err)
+
+			_, err = codec.Decode("role", encoded)
+			require.ErrorIs(t, err, ErrInvalidValue)
+		})
+
+		t.Run(name+" should reject a tampered value", func(t *testing.T) {
+			encoded, err := codec.Encode("theme", "dark")
+			require.NoError(t, err)
+
+			tampered := []byte(encoded)
+			tampered[0] ^= 1
+			_, err = codec.Decode("theme", string(tampered))
+			require.ErrorIs(t, err, ErrInvalidValue)
+		})
+	}
+
+	t.Run("encrypted value should not expose the plain value", func(t *testing.T) {
+		encoded, err := codecs["encrypted"].Encode("theme", "dark")
+		require.NoError(t, err)
+		require.NotContains(t, encoded, "ZGFyaw")
+	})
+
+	t.Run("should reject an expired value", func(t *testing.T) {
+		codec := &signedCodec{key: deriveKey(secret, "test"), maxAge: time.Hour}
+		payload := "1|ZGFyaw"
+		value := payload + separator + base64.RawURLEncoding.EncodeToString(codec.sign("theme", payload))
+
+		_, err := codec.Decode("theme", value)
+		require.ErrorIs(t, err, ErrExpiredValue)
+	})
+}
@@ -0,0 +1,298 @@
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/env"
+	"github.com/softwareplace/goserve/security/session"
+)
+
+const (
+	HandlerName      = "API_CSRF_MIDDLEWARE"
+	DefaultHeader    = "X-CSRF-Token"
+	DefaultFormField = "csrf_token"
+	DefaultCookie    = "goserve_csrf"
+
+	sessionValueKey = "goserve.csrf.token"
+)
+
+var (
+	ErrOriginMismatch = errors.New("request origin does not match the server origin")
+	ErrTokenMissing   = errors.New("request has no CSRF token")
+	ErrTokenMismatch  = errors.New("request CSRF token does not match")
+)
+
+// Mode is the strategy used to store the expected CSRF token.
+type Mode int
+
+const (
+	// Synchronizer keeps the token in the server-side session, so it requires a session.Manager.
+	Synchronizer Mode = iota
+	// DoubleSubmit keeps the token in a cookie readable by the client, which must send it back
+	// in a header or form field. It does not need any server-side state.
+	DoubleSubmit
+)
+
+// Option customizes a Protection.
+type Option func(*Protection)
+
+// Protection issues and validates CSRF tokens for state-changing requests of cookie-authenticated
+// clients. The token is accepted from the DefaultHeader, or from the DefaultFormField of
+// urlencoded forms. Multipart forms must send the header, so that their body is not buffered.
+type Protection struct {
+	mode           Mode
+	sessions       *session.Manager
+	header         string
+	formField      string
+	cookieName     string
+	secure         bool
+	trustedOrigins []string
+}
+
+// WithTrustedOrigins allows cross-origin requests from the given origins, such as `https://app.example.com`,
+// in addition to the origin of the server itself.
+func WithTrustedOrigins(origins ...string) Option {
+	return func(p *Protection) {
+		p.trustedOrigins = append(p.trustedOrigins, origins...)
+	}
+}
+
+// WithHeader sets the header and form field the token is read from.
+func WithHeader(header string, formField string) Option {
+	return func(p *Protection) {
+		p.header = header
+		p.formField = formField
+	}
+}
+
+// WithCookie sets the name and Secure attribute of the DoubleSubmit cookie.
+func WithCookie(name string, secure bool) Option {
+	return func(p *Protection) {
+		p.cookieName = name
+		p.secure = secure
+	}
+}
+
+// NewSynchronizer creates a Protection that keeps the token in the session of the request.
+// Requests without a session are not cookie-authenticated, so they are not checked.
+//
+// Parameters:
+//   - manager: The session.Manager that holds the sessions.
+//   - options: Options overriding the defaults.
+func NewSynchronizer(manager *session.Manager, options ...Option) *Protection {
+	return newProtection(Synchronizer, manager, options)
+}
+
+// NewDoubleSubmit creates a Protection that keeps the token in a cookie and expects the client to
+// send the same value back in the header or form field.
+//
+// Parameters:
+//   - options: Options overriding the defaults.
+func NewDoubleSubmit(options ...Option) *Protection {
+	return newProtection(DoubleSubmit, nil, options)
+}
+
+func newProtection(mode Mode, manager *session.Manager, options []Option) *Protection {
+	protection := &Protection{
+		mode:       mode,
+		sessions:   manager,
+		header:     DefaultHeader,
+		formField:  DefaultFormField,
+		cookieName: DefaultCookie,
+		secure:     env.GetBoolEnvOrDefault("SESSION_COOKIE_SECURE", true),
+	}
+
+	for _, option := range options {
+		option(protection)
+	}
+	return protection
+}
+
+// Mode returns the strategy of the Protection.
+func (p *Protection) Mode() Mode {
+	return p.mode
+}
+
+// FormField returns the form field of urlencoded forms holding the token.
+func (p *Protection) FormField() string {
+	return p.formField
+}
+
+// IsSafeMethod reports whether the method does not change state, so it does not need a CSRF token.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// Token returns the CSRF token of the request, issuing a new one if the request has none yet.
+//
+// Parameters:
+//   - w: The response writer, where the DoubleSubmit cookie is set.
+//   - r: The request.
+//   - s: The session of the request, if already loaded. It is loaded from the session.Manager otherwise.
+//
+// Returns:
+//   - string: The token to embed in forms or headers, or an empty string if a Synchronizer request has no session.
+//   - error: An error if the token could not be generated or stored.
+func (p *Protection) Token(w http.ResponseWriter, r *http.Request, s *session.Session) (string, error) {
+	if p.mode == DoubleSubmit {
+		if c, err := r.Cookie(p.cookieName); err == nil && c.Value != "" {
+			return c.Value, nil
+		}
+
+		token, err := newToken()
+		if err != nil {
+			return "", err
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     p.cookieName,
+			Value:    token,
+			Path:     "/",
+			Secure:   p.secure,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return token, nil
+	}
+
+	s = p.sessionOf(r, s)
+	if s == nil {
+		return "", nil
+	}
+
+	if token := s.Values[sessionValueKey]; token != "" {
+		return token, nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	if s.Values == nil {
+		s.Values = make(map[string]string)
+	}
+	s.Values[sessionValueKey] = token
+	return token, p.sessions.Save(s)
+}
+
+// Validate checks the origin and the CSRF token of a state-changing request.
+//
+// Parameters:
+//   - r: The request.
+//   - s: The session of the request, if already loaded. It is loaded from the session.Manager otherwise.
+//
+// Returns:
+//   - error: ErrOriginMismatch, ErrTokenMissing or ErrTokenMismatch if the request must be rejected.
+func (p *Protection) Validate(r *http.Request, s *session.Session) error {
+	if err := p.verifyOrigin(r); err != nil {
+		return err
+	}
+
+	var expected string
+	if p.mode == DoubleSubmit {
+		c, err := r.Cookie(p.cookieName)
+		if err != nil || c.Value == "" {
+			return ErrTokenMissing
+		}
+		expected = c.Value
+	} else {
+		s = p.sessionOf(r, s)
+		if s == nil {
+			return nil
+		}
+		expected = s.Values[sessionValueKey]
+		if expected == "" {
+			return ErrTokenMissing
+		}
+	}
+
+	actual := p.requestToken(r)
+	if actual == "" {
+		return ErrTokenMissing
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(actual)) != 1 {
+		return ErrTokenMismatch
+	}
+	return nil
+}
+
+func (p *Protection) sessionOf(r *http.Request, s *session.Session) *session.Session {
+	if s != nil || p.sessions == nil || !p.sessions.HasSession(r) {
+		return s
+	}
+
+	loaded, err := p.sessions.Load(r)
+	if err != nil {
+		log.Debugf("CSRF/SESSION: failed to load session: %v", err)
+		return nil
+	}
+	return loaded
+}
+
+func (p *Protection) requestToken(r *http.Request) string {
+	if token := r.Header.Get(p.header); token != "" {
+		return token
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		return r.PostFormValue(p.formField)
+	}
+	return ""
+}
+
+// verifyOrigin compares the Origin header, or the Referer when the Origin is missing, with the
+// server origin and the trusted origins. Requests carrying neither are left to the token check.
+func (p *Protection) verifyOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		referer := r.Header.Get("Referer")
+		if referer == "" {
+			return nil
+		}
+
+		parsed, err := url.Parse(referer)
+		if err != nil {
+			return ErrOriginMismatch
+		}
+		origin = parsed.Scheme + "://" + parsed.Host
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return ErrOriginMismatch
+	}
+
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return nil
+	}
+
+	for _, trusted := range p.trustedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(trusted, "/"), origin) {
+			return nil
+		}
+	}
+	return ErrOriginMismatch
+}
+
+func newToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,110 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/security/cookie"
+	"github.com/softwareplace/goserve/security/session"
+)
+
+func TestDoubleSubmit(t *testing.T) {
+	protection := NewDoubleSubmit()
+
+	rr := httptest.NewRecorder()
+	token, err := protection.Token(rr, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	tokenCookie := rr.Result().Cookies()[0]
+	require.Equal(t, DefaultCookie, tokenCookie.Name)
+	require.False(t, tokenCookie.HttpOnly)
+
+	t.Run("should reuse the token of the cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(tokenCookie)
+
+		reused, err := protection.Token(httptest.NewRecorder(), req, nil)
+		require.NoError(t, err)
+		require.Equal(t, token, reused)
+	})
+
+	t.Run("should accept the token sent in the header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.AddCookie(tokenCookie)
+		req.Header.Set(DefaultHeader, token)
+		require.NoError(t, protection.Validate(req, nil))
+	})
+
+	t.Run("should accept the token sent in an urlencoded form", func(t *testing.T) {
+		form := url.Values{DefaultFormField: {token}}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(tokenCookie)
+		require.NoError(t, protection.Validate(req, nil))
+	})
+
+	t.Run("should reject a missing or different token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.AddCookie(tokenCookie)
+		require.ErrorIs(t, protection.Validate(req, nil), ErrTokenMissing)
+
+		req.Header.Set(DefaultHeader, "other")
+		require.ErrorIs(t, protection.Validate(req, nil), ErrTokenMismatch)
+	})
+}
+
+func TestSynchronizer(t *testing.T) {
+	manager := session.New(
+		session.NewMemoryStore(),
+		session.WithCodec(cookie.NewSigned([]byte("DlJeR4%pPbB5Pr5cICMxg0xB"), 0)),
+	)
+	protection := NewSynchronizer(manager)
+
+	s, err := manager.Start(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", nil), "user-id", nil)
+	require.NoError(t, err)
+
+	token, err := protection.Token(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), s)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	t.Run("should accept the token stored in the session", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(DefaultHeader, token)
+		require.NoError(t, protection.Validate(req, s))
+	})
+
+	t.Run("should reject another token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(DefaultHeader, "other")
+		require.ErrorIs(t, protection.Validate(req, s), ErrTokenMismatch)
+	})
+
+	t.Run("should not check requests without session", func(t *testing.T) {
+		require.NoError(t, protection.Validate(httptest.NewRequest(http.MethodPost, "/", nil), nil))
+	})
+}
+
+func TestOrigin(t *testing.T) {
+	protection := NewDoubleSubmit(WithTrustedOrigins("https://app.example.com"))
+
+	request := func(header string, value string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://api.example.com/pets", nil)
+		req.AddCookie(&http.Cookie{Name: DefaultCookie, Value: "token"})
+		req.Header.Set(DefaultHeader, "token")
+		req.Header.Set(header, value)
+		return req
+	}
+
+	require.NoError(t, protection.Validate(request("Origin", "http://api.example.com"), nil))
+	require.NoError(t, protection.Validate(request("Origin", "https://app.example.com"), nil))
+	require.NoError(t, protection.Validate(request("Referer", "http://api.example.com/form"), nil))
+	require.ErrorIs(t, protection.Validate(request("Origin", "https://evil.example.com"), nil), ErrOriginMismatch)
+	require.ErrorIs(t, protection.Validate(request("Referer", "https://evil.example.com/form"), nil), ErrOriginMismatch)
+	require.ErrorIs(t, protection.Validate(request("Origin", "null"), nil), ErrOriginMismatch)
+}
@@ -0,0 +1,28 @@
+package login
+
+import (
+	"github.com/softwareplace/goserve/validator"
+)
+
+// CheckPassword checks the password of the user against the active validator.PasswordPolicy, using
+// the username, or the email when the username is empty, for the username similarity rule. Use it in
+// registration and password change flows; the login itself does not enforce the policy, so that
+// users with passwords created under an older policy can still sign in.
+//
+// Returns:
+//   - error: nil when the password follows the policy, or a *validator.PasswordError listing every
+//     failed rule, which ctx.Problem answers as a 422 problem.
+//
+// Example usage:
+//
+//	if err := user.CheckPassword(); err != nil {
+//		ctx.Problem(err)
+//		return
+//	}
+func (u User) CheckPassword() error {
+	username := u.Username
+	if username == "" {
+		username = u.Email
+	}
+	return validator.CheckPassword(u.Password, username)
+}
@@ -0,0 +1,23 @@
+package login
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/validator"
+)
+
+func TestUser_CheckPassword(t *testing.T) {
+	t.Setenv("PASSWORD_CHECK_USERNAME", "true")
+	validator.SetPasswordPolicy(validator.DefaultPasswordPolicy())
+	defer validator.SetPasswordPolicy(validator.DefaultPasswordPolicy())
+
+	require.NoError(t, User{Username: "maria", Password: "Str0ng&Secret"}.CheckPassword())
+
+	err := User{Email: "maria@example.com", Password: "Maria&2024"}.CheckPassword()
+
+	var passwordError *validator.PasswordError
+	require.ErrorAs(t, err, &passwordError)
+	require.Equal(t, validator.PasswordUsername, passwordError.Violations[0].Rule)
+}
@@ -6,6 +6,12 @@ import (
 	goservectx "github.com/softwareplace/goserve/context"
 )
 
+const (
+	ModeQueryParam = "mode"    // ModeQueryParam is the query parameter of the login resource that selects the login mode.
+	TokenMode      = "token"   // TokenMode answers the login with a JWT to be sent as bearer token. It is the default mode.
+	SessionMode    = "session" // SessionMode starts a server-side session and answers the login with a session cookie.
+)
+
 type User struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -15,7 +15,6 @@ import (
 
 	goservectx "github.com/softwareplace/goserve/context"
 	goserveerror "github.com/softwareplace/goserve/error"
-	"github.com/softwareplace/goserve/internal/utils"
 )
 
 // Helper function to generate a temporary RSA key file
@@ -56,12 +55,7 @@ func createTempECDSAKeyFile(t *testing.T, fileName string) string {
 func createTempKeyFile(t *testing.T, keyBytes []byte, fileName string) string {
 	t.Helper()
 
-	tmpDir := utils.ProjectBasePath() + "/.log/tmp"
-	err := os.MkdirAll(tmpDir, 0700)
-
-	require.NoError(t, err)
-
-	filePath := filepath.Join(tmpDir, fileName)
+	filePath := filepath.Join(t.TempDir(), fileName)
 
 	file, err := os.Create(filePath)
 	if err != nil {
@@ -114,8 +108,7 @@ func TestSecretImplValidationP2(t *testing.T) {
 		{
 			name: "invalid PEM data",
 			setup: func(t *testing.T) string {
-				tmpDir := utils.ProjectBasePath() + "/.log/tmp"
-				filePath := filepath.Join(tmpDir, "invalid.txt")
+				filePath := filepath.Join(t.TempDir(), "invalid.txt")
 
 				if err := os.WriteFile(filePath, []byte("not a valid key"), 0600); err != nil {
 					t.Fatal(err)
@@ -8,6 +8,7 @@ import (
 	goserveerror "github.com/softwareplace/goserve/error"
 	"github.com/softwareplace/goserve/security/jwt"
 	"github.com/softwareplace/goserve/security/principal"
+	"github.com/softwareplace/goserve/security/session"
 )
 
 const (
@@ -46,10 +47,29 @@ type Service[T goservectx.Principal] interface {
 	AuthorizationHandler(ctx *goservectx.Request[T]) (doNext bool)
 }
 
+// SessionAuthorizer is implemented by the Service values that can authorize requests by session
+// cookie, such as the one created by New. It is optional, so other Service implementations keep
+// working: the server detects it with a type assertion when a session.Manager is configured.
+type SessionAuthorizer[T goservectx.Principal] interface {
+	// SessionManager enables the cookie-session authorization. Requests without an Authorization
+	// header but carrying a session cookie are authorized by the session: the session principal id
+	// is set as ctx.AccessId and the Session is stored under session.Key before the principal is loaded.
+	//
+	// Parameters:
+	//   - manager: The session.Manager that validates the session cookies.
+	//
+	// Returns:
+	//   - Service[T]: The same Service, for chaining.
+	SessionManager(manager *session.Manager) Service[T]
+}
+
+var _ SessionAuthorizer[goservectx.Principal] = (*impl[goservectx.Principal])(nil)
+
 type impl[T goservectx.Principal] struct {
 	ResourceAccessValidation[T]
 	jwt.Service[T]
 	PService principal.Service[T]
+	sessions *session.Manager
 }
 
 // New creates a new instance of the security Service with a default error handler.
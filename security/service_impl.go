@@ -1,8 +1,11 @@
 package security
 
 import (
+	log "github.com/sirupsen/logrus"
+
 	goservectx "github.com/softwareplace/goserve/context"
 	"github.com/softwareplace/goserve/security/router"
+	"github.com/softwareplace/goserve/security/session"
 )
 
 func (a *impl[T]) AuthorizationHandler(ctx *goservectx.Request[T]) (doNext bool) {
@@ -10,6 +13,13 @@ func (a *impl[T]) AuthorizationHandler(ctx *goservectx.Request[T]) (doNext bool)
 		return true
 	}
 
+	if ctx.Authorization == "" && a.sessions != nil && a.sessions.HasSession(ctx.Request) {
+		if !a.loadSession(ctx) {
+			return false
+		}
+		return a.PService.LoadPrincipal(ctx)
+	}
+
 	if !a.ExtractJWTClaims(ctx) {
 		ctx.Forbidden("Invalid JWT token")
 		return false
@@ -17,3 +27,21 @@ func (a *impl[T]) AuthorizationHandler(ctx *goservectx.Request[T]) (doNext bool)
 
 	return a.PService.LoadPrincipal(ctx)
 }
+
+func (a *impl[T]) SessionManager(manager *session.Manager) Service[T] {
+	a.sessions = manager
+	return a
+}
+
+func (a *impl[T]) loadSession(ctx *goservectx.Request[T]) bool {
+	s, err := a.sessions.Load(ctx.Request)
+	if err != nil {
+		log.Errorf("SESSION/LOAD: AuthorizationHandler failed: %v", err)
+		ctx.Unauthorized()
+		return false
+	}
+
+	ctx.AccessId = s.PrincipalId
+	goservectx.Set(ctx, session.Key, s)
+	return true
+}
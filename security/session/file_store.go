@@ -0,0 +1,86 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type fileStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFileStore creates a Store that keeps each session as a JSON file in the given directory,
+// so sessions survive restarts. The directory is created if it does not exist.
+//
+// Parameters:
+//   - dir: The directory where the session files are stored.
+//
+// Returns:
+//   - Store: The file based Store.
+//   - error: An error if the directory could not be created.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (f *fileStore) Load(id string) (*Session, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	data, err := os.ReadFile(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err = json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (f *fileStore) Save(session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Write to a temporary file first, so a concurrent reader never sees a partial session.
+	path := f.path(session.Id)
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (f *fileStore) Delete(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := os.Remove(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path hashes the session id, so the id is never used as a file name.
+func (f *fileStore) path(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return filepath.Join(f.dir, hex.EncodeToString(hash[:])+".json")
+}
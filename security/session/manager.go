@@ -0,0 +1,281 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/env"
+	"github.com/softwareplace/goserve/security/cookie"
+)
+
+var (
+	ErrNoSession = errors.New("request has no session cookie")
+	ErrExpired   = errors.New("session has expired")
+)
+
+// Option customizes a Manager created with New.
+type Option func(*Manager)
+
+// Manager starts, loads, rotates and destroys sessions, keeping the session id in a signed cookie
+// and the session state in a Store.
+type Manager struct {
+	store           Store
+	codec           cookie.Codec
+	cookieName      string
+	cookiePath      string
+	cookieDomain    string
+	secure          bool
+	sameSite        http.SameSite
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
+}
+
+// WithCodec sets the codec used to protect the session cookie. By default, the cookie is signed
+// with the API_SECRET_KEY.
+func WithCodec(codec cookie.Codec) Option {
+	return func(m *Manager) {
+		m.codec = codec
+	}
+}
+
+// WithIdleTimeout sets how long a session stays valid without requests. Zero disables it.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.idleTimeout = timeout
+	}
+}
+
+// WithAbsoluteTimeout sets how long a session stays valid since it started, regardless of activity.
+func WithAbsoluteTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.absoluteTimeout = timeout
+	}
+}
+
+// WithCookie sets the name, path and domain of the session cookie.
+func WithCookie(name string, path string, domain string) Option {
+	return func(m *Manager) {
+		m.cookieName = name
+		m.cookiePath = path
+		m.cookieDomain = domain
+	}
+}
+
+// WithSecureCookie sets the Secure and SameSite attributes of the session cookie.
+func WithSecureCookie(secure bool, sameSite http.SameSite) Option {
+	return func(m *Manager) {
+		m.secure = secure
+		m.sameSite = sameSite
+	}
+}
+
+// New creates a session Manager backed by the given Store.
+//
+// The defaults are loaded from the environment:
+//   - SESSION_COOKIE_NAME: The session cookie name. Default `goserve_session`.
+//   - SESSION_COOKIE_SECURE: Whether the cookie is only sent over HTTPS. Default `true`.
+//   - SESSION_IDLE_TIMEOUT: The idle timeout, such as `30m`. Default `30m`.
+//   - SESSION_ABSOLUTE_TIMEOUT: The absolute timeout, such as `24h`. Default `24h`.
+//
+// Parameters:
+//   - store: The Store that persists the sessions, such as NewMemoryStore or NewFileStore.
+//   - options: Options overriding the defaults.
+//
+// Returns:
+//   - *Manager: The session Manager.
+//
+// Example usage:
+//
+//	manager := session.New(session.NewMemoryStore(), session.WithIdleTimeout(15*time.Minute))
+//	server.Default().
+//		SessionManager(manager).
+//		SecurityService(securityService)
+func New(store Store, options ...Option) *Manager {
+	manager := &Manager{
+		store:           store,
+		cookieName:      env.GetEnvOrDefault("SESSION_COOKIE_NAME", "goserve_session"),
+		cookiePath:      "/",
+		secure:          env.GetBoolEnvOrDefault("SESSION_COOKIE_SECURE", true),
+		sameSite:        http.SameSiteLaxMode,
+		idleTimeout:     durationFromEnv("SESSION_IDLE_TIMEOUT", 30*time.Minute),
+		absoluteTimeout: durationFromEnv("SESSION_ABSOLUTE_TIMEOUT", 24*time.Hour),
+	}
+
+	for _, option := range options {
+		option(manager)
+	}
+
+	if manager.codec == nil {
+		apiSecretKey := env.GetEnvOrDefault("API_SECRET_KEY", "")
+		if apiSecretKey == "" {
+			log.Fatal("API_SECRET_KEY environment variable is not set")
+		}
+		manager.codec = cookie.NewSigned([]byte(apiSecretKey), 0)
+	}
+	return manager
+}
+
+// CookieName returns the name of the session cookie.
+func (m *Manager) CookieName() string {
+	return m.cookieName
+}
+
+// HasSession reports whether the request carries a session cookie, without validating it.
+func (m *Manager) HasSession(r *http.Request) bool {
+	_, err := r.Cookie(m.cookieName)
+	return err == nil
+}
+
+// Start starts a new session for the principal and sets the session cookie. Any session the
+// request already carries is destroyed, so the session id always changes on login.
+//
+// Parameters:
+//   - w: The response writer, where the session cookie is set.
+//   - r: The login request.
+//   - principalId: The id of the logged-in principal.
+//   - roles: The roles of the logged-in principal.
+//
+// Returns:
+//   - *Session: The new session.
+//   - error: An error if the session could not be stored.
+func (m *Manager) Start(w http.ResponseWriter, r *http.Request, principalId string, roles []string) (*Session, error) {
+	if id, err := m.idOf(r); err == nil {
+		if err = m.store.Delete(id); err != nil {
+			log.Errorf("SESSION/START: failed to delete previous session: %v", err)
+		}
+	}
+
+	now := time.Now()
+	session := &Session{
+		PrincipalId:  principalId,
+		Roles:        roles,
+		CreatedAt:    now,
+		LastAccessAt: now,
+	}
+
+	if m.absoluteTimeout > 0 {
+		session.ExpiresAt = now.Add(m.absoluteTimeout)
+	}
+
+	return session, m.issue(w, session)
+}
+
+// Load returns the valid session of the request and records the access, extending the idle timeout.
+//
+// Returns:
+//   - *Session: The session of the request.
+//   - error: ErrNoSession if the request has no session cookie, cookie.ErrInvalidValue if the cookie
+//     was tampered with, ErrNotFound if the session does not exist, or ErrExpired if it timed out.
+func (m *Manager) Load(r *http.Request) (*Session, error) {
+	id, err := m.idOf(r)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := m.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.expired(now, m.idleTimeout) {
+		if err = m.store.Delete(id); err != nil {
+			log.Errorf("SESSION/LOAD: failed to delete expired session: %v", err)
+		}
+		return nil, ErrExpired
+	}
+
+	session.LastAccessAt = now
+	return session, m.store.Save(session)
+}
+
+// Save stores the changes made to the session, such as its Values.
+func (m *Manager) Save(session *Session) error {
+	return m.store.Save(session)
+}
+
+// Rotate gives the session a new id, keeping its state, and sets the new session cookie.
+// Call it whenever the privileges of the session change.
+func (m *Manager) Rotate(w http.ResponseWriter, session *Session) (*Session, error) {
+	previousId := session.Id
+
+	if err := m.issue(w, session); err != nil {
+		return nil, err
+	}
+	return session, m.store.Delete(previousId)
+}
+
+// Destroy deletes the session of the request, if any, and expires the session cookie.
+func (m *Manager) Destroy(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, m.cookie("", time.Unix(0, 0)))
+
+	id, err := m.idOf(r)
+	if err != nil {
+		return nil
+	}
+	return m.store.Delete(id)
+}
+
+// issue assigns a new id to the session, stores it and sets the session cookie.
+func (m *Manager) issue(w http.ResponseWriter, session *Session) error {
+	id, err := newId()
+	if err != nil {
+		return err
+	}
+
+	value, err := m.codec.Encode(m.cookieName, id)
+	if err != nil {
+		return err
+	}
+
+	session.Id = id
+	if err = m.store.Save(session); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, m.cookie(value, session.ExpiresAt))
+	return nil
+}
+
+func (m *Manager) idOf(r *http.Request) (string, error) {
+	c, err := r.Cookie(m.cookieName)
+	if err != nil {
+		return "", ErrNoSession
+	}
+	return m.codec.Decode(m.cookieName, c.Value)
+}
+
+func (m *Manager) cookie(value string, expires time.Time) *http.Cookie {
+	c := &http.Cookie{
+		Name:     m.cookieName,
+		Value:    value,
+		Path:     m.cookiePath,
+		Domain:   m.cookieDomain,
+		Secure:   m.secure,
+		HttpOnly: true,
+		SameSite: m.sameSite,
+		Expires:  expires,
+	}
+
+	if value == "" {
+		c.MaxAge = -1
+	}
+	return c
+}
+
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value := env.GetEnvOrDefault(key, "")
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Errorf("%s must be a valid duration, such as 30m: %v", key, err)
+		return defaultValue
+	}
+	return duration
+}
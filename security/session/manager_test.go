@@ -0,0 +1,129 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/softwareplace/goserve/security/cookie"
+)
+
+func newTestManager(store Store, options ...Option) *Manager {
+	options = append([]Option{
+		WithCodec(cookie.NewSigned([]byte("DlJeR4%pPbB5Pr5cICMxg0xB"), 0)),
+	}, options...)
+	return New(store, options...)
+}
+
+func requestWith(cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func start(t *testing.T, manager *Manager, req *http.Request) (*Session, *http.Cookie) {
+	rr := httptest.NewRecorder()
+	s, err := manager.Start(rr, req, "user-id", []string{"read:pets"})
+	require.NoError(t, err)
+
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.True(t, cookies[0].HttpOnly)
+	return s, cookies[0]
+}
+
+func TestManager(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name+" should load the started session", func(t *testing.T) {
+			manager := newTestManager(store)
+			started, c := start(t, manager, requestWith())
+
+			loaded, err := manager.Load(requestWith(c))
+			require.NoError(t, err)
+			require.Equal(t, started.Id, loaded.Id)
+			require.Equal(t, "user-id", loaded.PrincipalId)
+			require.Equal(t, []string{"read:pets"}, loaded.Roles)
+		})
+
+		t.Run(name+" should rotate the session id on login", func(t *testing.T) {
+			manager := newTestManager(store)
+			first, firstCookie := start(t, manager, requestWith())
+			second, secondCookie := start(t, manager, requestWith(firstCookie))
+
+			require.NotEqual(t, first.Id, second.Id)
+
+			_, err := manager.Load(requestWith(firstCookie))
+			require.ErrorIs(t, err, ErrNotFound)
+
+			_, err = manager.Load(requestWith(secondCookie))
+			require.NoError(t, err)
+		})
+
+		t.Run(name+" should destroy the session", func(t *testing.T) {
+			manager := newTestManager(store)
+			_, c := start(t, manager, requestWith())
+
+			rr := httptest.NewRecorder()
+			require.NoError(t, manager.Destroy(rr, requestWith(c)))
+			require.Equal(t, -1, rr.Result().Cookies()[0].MaxAge)
+
+			_, err := manager.Load(requestWith(c))
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+
+	t.Run("should reject an idle session", func(t *testing.T) {
+		manager := newTestManager(NewMemoryStore(), WithIdleTimeout(time.Minute))
+		s, c := start(t, manager, requestWith())
+
+		s.LastAccessAt = time.Now().Add(-2 * time.Minute)
+		require.NoError(t, manager.Save(s))
+
+		_, err := manager.Load(requestWith(c))
+		require.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("should reject a session past its absolute timeout", func(t *testing.T) {
+		manager := newTestManager(NewMemoryStore(), WithAbsoluteTimeout(time.Hour))
+		s, c := start(t, manager, requestWith())
+
+		s.ExpiresAt = time.Now().Add(-time.Second)
+		require.NoError(t, manager.Save(s))
+
+		_, err := manager.Load(requestWith(c))
+		require.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("should reject a forged cookie", func(t *testing.T) {
+		manager := newTestManager(NewMemoryStore())
+		_, err := manager.Load(requestWith(&http.Cookie{Name: manager.CookieName(), Value: "forged"}))
+		require.ErrorIs(t, err, cookie.ErrInvalidValue)
+	})
+
+	t.Run("should keep the session state on rotate", func(t *testing.T) {
+		manager := newTestManager(NewMemoryStore())
+		s, c := start(t, manager, requestWith())
+		previousId := s.Id
+
+		rotated, err := manager.Rotate(httptest.NewRecorder(), s)
+		require.NoError(t, err)
+		require.NotEqual(t, previousId, rotated.Id)
+		require.Equal(t, "user-id", rotated.PrincipalId)
+
+		_, err = manager.Load(requestWith(c))
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
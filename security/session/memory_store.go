@@ -0,0 +1,67 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+const memoryStoreSweepInterval = time.Minute
+
+type memoryStore struct {
+	mu        sync.RWMutex
+	sessions  map[string]Session
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a Store that keeps the sessions in memory. Sessions are lost when
+// the process restarts and are not shared between instances, so it is intended for single
+// instance deployments, development and tests.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		sessions:  make(map[string]Session),
+		lastSweep: time.Now(),
+	}
+}
+
+func (m *memoryStore) Load(id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+func (m *memoryStore) Save(session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.Id] = *session
+	m.sweep()
+	return nil
+}
+
+func (m *memoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
+
+// sweep drops the sessions past their absolute expiry, so that abandoned sessions do not pile up.
+func (m *memoryStore) sweep() {
+	now := time.Now()
+	if now.Sub(m.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+
+	m.lastSweep = now
+	for id, session := range m.sessions {
+		if !session.ExpiresAt.IsZero() && now.After(session.ExpiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
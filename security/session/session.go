@@ -0,0 +1,57 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	goservectx "github.com/softwareplace/goserve/context"
+)
+
+// Key is the attribute key under which the security service stores the Session of
+// requests authorized by a session cookie.
+var Key = goservectx.NewKey[*Session]("goserve.session")
+
+// Session is the server-side state of a browser client logged in with the cookie-session mode.
+// Only the session Id is sent to the client, inside a signed cookie.
+type Session struct {
+	Id           string            `json:"id"`               // Id is the random identifier of the session, sent to the client in the session cookie.
+	PrincipalId  string            `json:"principalId"`      // PrincipalId identifies the logged-in principal. It is exposed as Request.AccessId.
+	Roles        []string          `json:"roles,omitempty"`  // Roles are the roles of the principal when the session started.
+	Values       map[string]string `json:"values,omitempty"` // Values holds application data stored on the session. Call Manager.Save after changing it.
+	CreatedAt    time.Time         `json:"createdAt"`        // CreatedAt is the moment the session started.
+	LastAccessAt time.Time         `json:"lastAccessAt"`     // LastAccessAt is the moment of the last request authorized by the session.
+	ExpiresAt    time.Time         `json:"expiresAt"`        // ExpiresAt is the absolute expiry of the session, regardless of activity.
+}
+
+// Response is the login response body of the cookie-session mode.
+type Response struct {
+	Expires  int `json:"expires"`
+	IssuedAt int `json:"issuedAt"`
+}
+
+// From returns the Session stored on the request, if the request was authorized by a session cookie.
+//
+// Example usage:
+//
+//	if s, ok := session.From(ctx); ok {
+//		log.Printf("session started at %v", s.CreatedAt)
+//	}
+func From(holder goservectx.AttributeHolder) (*Session, bool) {
+	return goservectx.Get(holder, Key)
+}
+
+func (s *Session) expired(now time.Time, idleTimeout time.Duration) bool {
+	if !s.ExpiresAt.IsZero() && now.After(s.ExpiresAt) {
+		return true
+	}
+	return idleTimeout > 0 && now.Sub(s.LastAccessAt) > idleTimeout
+}
+
+func newId() (string, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
@@ -0,0 +1,26 @@
+package session
+
+import (
+	"errors"
+)
+
+var ErrNotFound = errors.New("session not found")
+
+// Store persists sessions. Implementations must be safe for concurrent use.
+// NewMemoryStore and NewFileStore are provided, and any other storage, such as a
+// database or a cache, can be plugged in by implementing this interface.
+type Store interface {
+
+	// Load returns the session with the given id.
+	//
+	// Returns:
+	//   - *Session: The stored session.
+	//   - error: ErrNotFound if there is no session with the given id.
+	Load(id string) (*Session, error)
+
+	// Save creates or replaces the session.
+	Save(session *Session) error
+
+	// Delete removes the session with the given id. Deleting a missing session is not an error.
+	Delete(id string) error
+}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	log "github.com/sirupsen/logrus"
+
 	"github.com/softwareplace/goserve/security"
 	"github.com/softwareplace/goserve/security/secret"
+	"github.com/softwareplace/goserve/security/session"
 )
 
 func (a *baseServer[T]) SecretService(service secret.Service[T]) Api[T] {
@@ -16,6 +19,28 @@ func (a *baseServer[T]) SecretService(service secret.Service[T]) Api[T] {
 
 func (a *baseServer[T]) SecurityService(service security.Service[T]) Api[T] {
 	a.securityService = service
+	if a.sessionManager != nil {
+		a.useSessions(service, a.sessionManager)
+	}
 	return a.RegisterMiddleware(service.AuthorizationHandler, security.ApiSecurityHandlerName).
 		EmbeddedServer(func(Api[T]) { a.router.Use(service.HasResourceAccess) })
 }
+
+func (a *baseServer[T]) SessionManager(manager *session.Manager) Api[T] {
+	a.sessionManager = manager
+	if a.securityService != nil {
+		a.useSessions(a.securityService, manager)
+	}
+	return a
+}
+
+// useSessions enables the cookie-session authorization of the security service, when it supports it.
+func (a *baseServer[T]) useSessions(service security.Service[T], manager *session.Manager) {
+	authorizer, ok := service.(security.SessionAuthorizer[T])
+	if !ok {
+		log.Warnf("SESSION: the security service %T does not implement security.SessionAuthorizer, "+
+			"so sessions will not authorize requests", service)
+		return
+	}
+	authorizer.SessionManager(manager)
+}
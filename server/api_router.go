@@ -1,15 +1,21 @@
 package server
 
 import (
+	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
 
 	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/openapi"
 	"github.com/softwareplace/goserve/security"
+	"github.com/softwareplace/goserve/security/csrf"
 	"github.com/softwareplace/goserve/security/login"
 	"github.com/softwareplace/goserve/security/secret"
+	"github.com/softwareplace/goserve/security/session"
+	"github.com/softwareplace/goserve/view"
 )
 
 type ApiContextHandler[T goservectx.Principal] func(ctx *goservectx.Request[T])
@@ -144,6 +150,126 @@ type Api[T goservectx.Principal] interface {
 	//   - Api[T]: The router handler for chaining further route configurations.
 	Head(handler ApiContextHandler[T], path string, requiredRoles ...string) Api[T]
 
+	// RouteOptions customizes the behaviour of a single route, such as its request deadline.
+	// The options apply to the route registered with the same path and method, whether it is
+	// registered before or after calling this method.
+	//
+	// Parameters:
+	//   - path: The URL route path, as given to Add or the method-specific registration functions.
+	//   - method: The HTTP method for the route.
+	//   - options: The RouteOption values to apply, such as WithTimeout.
+	//
+	// Example usage:
+	// ```go
+	// api.Get(reportHandler, "/reports").
+	//	RouteOptions("/reports", "GET", server.WithTimeout(2*time.Minute))
+	// ```
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further route configurations.
+	RouteOptions(path string, method string, options ...RouteOption) Api[T]
+
+	// ETag enables ETags on the JSON responses of all routes. GET and HEAD requests whose
+	// If-None-Match header matches the ETag of the response are answered with 304 Not Modified.
+	// Routes can override it with RouteOptions and WithETag. Use ctx.IfMatch in update handlers
+	// for optimistic concurrency.
+	//
+	// Parameters:
+	//   - mode: goservectx.ETagWeak, goservectx.ETagStrong or goservectx.ETagDisabled.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	ETag(mode goservectx.ETagMode) Api[T]
+
+	// ErrorFormat selects the body of the error responses of this server: goservectx.ProblemFormat,
+	// RFC 7807 `application/problem+json`, or goservectx.LegacyFormat, the
+	// `{"message", "statusCode", "timestamp"}` object. By default, it is set by the
+	// ERROR_RESPONSE_FORMAT environment variable and falls back to the problem format.
+	//
+	// Handlers can answer typed errors, such as problem.NotFound, with ctx.Problem or by
+	// panicking with them, and the error handler answers them with their status.
+	//
+	// Parameters:
+	//   - format: The error format.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	ErrorFormat(format goservectx.ErrorFormat) Api[T]
+
+	// Envelope wraps the JSON responses of all routes, including the health, login and api-key
+	// resources and the errors, in the `{"data", "meta", "errors"}` object of goservectx.DataEnvelope,
+	// or in the object built by its Strategy. Its Meta hooks add members such as the request id or
+	// timing, and paginated bodies, such as query.Page, add their pagination to the meta.
+	// Routes can opt out with RouteOptions and WithoutEnvelope. File downloads, the Swagger JSON and
+	// the mock server responses are never enveloped.
+	//
+	// Parameters:
+	//   - envelope: The envelope, or nil to disable it.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	//
+	// Example usage:
+	//
+	//	api.Envelope(&goservectx.Envelope{
+	//		Meta: []goservectx.MetaHook{goservectx.RequestIdMeta, goservectx.TimingMeta},
+	//	})
+	Envelope(envelope *goservectx.Envelope) Api[T]
+
+	// Views sets the html/template engine used by ctx.HTML to render server-side pages, such as the
+	// ones of admin tools. See view.New.
+	//
+	// Parameters:
+	//   - engine: The template engine.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	//
+	// Example usage:
+	//
+	//	engine, err := view.New(os.DirFS("views"))
+	//	api.Views(engine).Get(func(ctx *goservectx.Request[*Principal]) {
+	//		ctx.HTML(http.StatusOK, "dashboard", nil)
+	//	}, "/admin")
+	Views(engine *view.Engine) Api[T]
+
+	// Transactions opens a database/sql transaction for the requests of the scope, available to
+	// handlers as ctx.Tx(). The transaction is committed when the handler answers a status below
+	// 400, and rolled back on error responses and on panics, which errorHandlerWrapper then
+	// answers. It begins with ctx.Context(), so it is also rolled back when the request deadline is
+	// exceeded. Routes configure it with RouteOptions and WithTransaction, WithReadOnlyTransaction
+	// or WithoutTransaction.
+	//
+	// The transaction begins after every middleware, so requests rejected by the security
+	// middlewares never open one, and it is committed right before the headers of the response are
+	// sent. A failed commit is logged and answered with a 500 instead of the handler response.
+	//
+	// Parameters:
+	//   - db: The database, or nil to disable the transactions.
+	//   - scope: TransactionPerRequest or TransactionPerRoute.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	//
+	// Example usage:
+	//
+	//	api.Transactions(db, server.TransactionPerRequest).
+	//		RouteOptions("/pets", "GET", server.WithReadOnlyTransaction())
+	Transactions(db *sql.DB, scope TransactionScope) Api[T]
+
+	// RequestTimeout sets the global request deadline. The deadline is applied to the request context,
+	// available to handlers as ctx.Context(), which is canceled once it is exceeded or the client disconnects.
+	// Routes can override it with RouteOptions and WithTimeout.
+	//
+	// Parameters:
+	//   - timeout: The request deadline. Zero disables it.
+	// Default:
+	//   - REQUEST_TIMEOUT environment variable, e.g. 30s. Disabled when not set.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	RequestTimeout(timeout time.Duration) Api[T]
+
 	// RegisterMiddleware adds a middleware function to the API router.
 	// Middleware intercepts requests and can perform tasks like authentication, logging, etc.
 	//
@@ -181,6 +307,40 @@ type Api[T goservectx.Principal] interface {
 	//   - Api[T]: The router handler for chaining additional route or service configurations.
 	SecurityService(service security.Service[T]) Api[T]
 
+	// SessionManager enables the cookie-session mode for browser clients. The login resource
+	// starts a session when called with `?mode=session`, answering with a signed session cookie
+	// instead of a JWT, and the security.Service accepts either the Authorization header or the
+	// session cookie. It can be called before or after SecurityService.
+	//
+	// Parameters:
+	//   - manager: The session.Manager, created with session.New and a session.Store.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining additional route or service configurations.
+	//
+	// Example:
+	//	 - server.New[...]().
+	//	 		SessionManager(session.New(session.NewMemoryStore())).
+	//	 		SecurityService(mySecurityServiceImpl)
+	SessionManager(manager *session.Manager) Api[T]
+
+	// CsrfProtection registers a middleware that protects state-changing requests of cookie-authenticated
+	// clients against cross-site request forgery. It checks the Origin, or Referer, header and the
+	// CSRF token, using either synchronizer tokens kept in the session or double-submit cookies.
+	//
+	// Safe methods, requests carrying an X-Api-Key or Authorization header, paths registered
+	// through PublicRouter and routes configured with WithoutCsrf are not checked. The token of
+	// the request is available as ctx.CsrfToken(), for templated responses.
+	//
+	// Call it after SecurityService, so that the session of the request is already loaded.
+	//
+	// Parameters:
+	//   - protection: The csrf.Protection, created with csrf.NewSynchronizer or csrf.NewDoubleSubmit.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining additional route or service configurations.
+	CsrfProtection(protection *csrf.Protection) Api[T]
+
 	// RegisterCustomMiddleware is a method that allows for registering a custom middleware function
 	// with the API router. This function wraps an HTTP handler and can be used to implement custom
 	// functionality, such as modifying the request or response, logging, or adding additional
@@ -391,6 +551,48 @@ type Api[T goservectx.Principal] interface {
 	//   - Api[T]: The router handler for chaining further route configurations.
 	SwaggerDocHandler(swaggerFile string) Api[T]
 
+	// MockServer enables or disables the mock server mode.
+	// When enabled, every operation of the spec loaded by SwaggerDocProvider or SwaggerDocHandler that
+	// has no registered handler answers with its documented `example`/`examples` or with a payload
+	// synthesized from the response schema. Handlers registered before the server starts take precedence.
+	//
+	// Clients can pick a specific documented response with the Prefer header:
+	//   - `Prefer: code=404` answers with the documented 404 response.
+	//   - `Prefer: example=name` answers with the documented example called name.
+	//   - Both can be combined, e.g. `Prefer: code=404, example=notFound`.
+	//
+	// Parameters:
+	//   - enable: A boolean value indicating whether to enable (true) or disable (false) the mock server mode.
+	// Default:
+	//   - MOCK_SERVER_ENABLED environment variable, false when not set.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	MockServer(enable bool) Api[T]
+
+	// ConformanceCheck enables the spec conformance check that runs when the server starts.
+	// The check compares the operations of the spec loaded by SwaggerDocProvider or SwaggerDocHandler,
+	// after the context path rewrite, with the registered routes and their required roles.
+	// Every issue found is logged as a warning.
+	//
+	// Parameters:
+	//   - strict: When true, any issue found fails the server startup.
+	//
+	// Returns:
+	//   - Api[T]: The router handler for chaining further configurations.
+	ConformanceCheck(strict bool) Api[T]
+
+	// Conformance compares the operations of the loaded spec with the registered routes and returns
+	// a structured report of missing handlers, undocumented routes and security scope mismatches.
+	// goserve's own resources, such as health and login, are not reported when undocumented.
+	// It can be used as a test helper:
+	//
+	//	require.NoError(t, api.Conformance().Err())
+	//
+	// Returns:
+	//   - openapi.ConformanceReport: The issues found.
+	Conformance() openapi.ConformanceReport
+
 	// NotFoundHandler sets a custom handler for requests to undefined routes.
 	// This method can be used to provide a user-friendly response or logging
 	// for routes that are not registered within the API router.
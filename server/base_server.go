@@ -1,17 +1,23 @@
 package server
 
 import (
+	"database/sql"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gorilla/mux"
 
 	goservectx "github.com/softwareplace/goserve/context"
 	"github.com/softwareplace/goserve/env"
 	goserveerror "github.com/softwareplace/goserve/error"
 	"github.com/softwareplace/goserve/security"
+	"github.com/softwareplace/goserve/security/csrf"
 	"github.com/softwareplace/goserve/security/login"
 	"github.com/softwareplace/goserve/security/secret"
+	"github.com/softwareplace/goserve/security/session"
+	"github.com/softwareplace/goserve/view"
 )
 
 type baseServer[T goservectx.Principal] struct {
@@ -20,12 +26,29 @@ type baseServer[T goservectx.Principal] struct {
 	loginService                        login.Service[T]
 	securityService                     security.Service[T]
 	secretService                       secret.Service[T]
+	sessionManager                      *session.Manager
+	csrfProtection                      *csrf.Protection
 	server                              *http.Server // Add a server instance
 	mu                                  sync.Mutex   // Add a mutex for thread safety
 	swaggerIsEnabled                    bool
 	loginResourceEnable                 bool
 	apiSecretKeyGeneratorResourceEnable bool
 	healthResourceEnable                bool
+	mockServerEnable                    bool
+	mockResourcesOnce                   sync.Once
+	serveResourcesOnce                  sync.Once
+	conformanceCheckEnable              bool
+	conformanceCheckStrict              bool
+	requestTimeout                      time.Duration
+	etagMode                            goservectx.ETagMode
+	errorFormat                         goservectx.ErrorFormat
+	envelope                            *goservectx.Envelope
+	views                               *view.Engine
+	transactionDB                       *sql.DB
+	transactionScope                    TransactionScope
+	routeSettings                       map[string]*RouteSettings
+	routeSettingsLock                   sync.RWMutex
+	swagger                             *openapi3.T
 	contextPath                         string
 	port                                string
 }
@@ -62,29 +85,39 @@ func New[T goservectx.Principal](topMiddlewares ...ApiMiddleware[T]) Api[T] {
 }
 
 func create[T goservectx.Principal](topMiddlewares ...ApiMiddleware[T]) *baseServer[T] {
-	router := mux.NewRouter()
-	router.Use(rootAppMiddleware[T])
+	api := newBaseServer[T](mux.NewRouter())
 
+	for _, middleware := range topMiddlewares {
+		api.RegisterMiddleware(middleware, "")
+	}
+	return api
+}
+
+// newBaseServer creates the baseServer of the router with its default options and installs the
+// middleware chain shared by every constructor, so that the server settings, such as the request
+// deadline, the route options, the ETag mode or the envelope, apply whatever the constructor.
+func newBaseServer[T goservectx.Principal](router *mux.Router) *baseServer[T] {
 	api := &baseServer[T]{
 		router:                              router,
 		apiSecretKeyGeneratorResourceEnable: true,
 		loginResourceEnable:                 true,
 		healthResourceEnable:                true,
+		mockServerEnable:                    env.GetBoolEnvOrDefault("MOCK_SERVER_ENABLED", false),
+		requestTimeout:                      requestTimeoutFromEnv(),
 		contextPath:                         env.APIContextPath(),
 		port:                                apiPort(),
 	}
 
+	router.Use(rootAppMiddleware[T])
 	router.Use(api.errorHandlerWrapper)
-
-	for _, middleware := range topMiddlewares {
-		api.RegisterMiddleware(middleware, "")
-	}
+	router.Use(api.requestDeadlineMiddleware)
+	router.Use(api.routeSettingsMiddleware)
 	return api
 }
 
 // NewWith initializes and returns a new instance of the Api[T] interface using a provided Gorilla mux router.
-// It wraps the provided router with the baseServer configuration, adds the root application middleware,
-// and sets default options such as the context path and port.
+// It wraps the provided router with the baseServer configuration, adds the same middleware chain as
+// the other constructors, and sets default options such as the context path and port.
 //
 // Parameters:
 //   - T: A type that implements the goservectx.Principal interface.
@@ -93,15 +126,5 @@ func create[T goservectx.Principal](topMiddlewares ...ApiMiddleware[T]) *baseSer
 // Returns:
 //   - Api[T]: An instance of the Api[T] interface configured with the provided router.
 func NewWith[T goservectx.Principal](router mux.Router) Api[T] {
-	router.Use(rootAppMiddleware[T])
-	api := &baseServer[T]{
-		router:                              &router,
-		apiSecretKeyGeneratorResourceEnable: true,
-		healthResourceEnable:                true,
-		loginResourceEnable:                 true,
-		contextPath:                         env.APIContextPath(),
-		port:                                apiPort(),
-	}
-
-	return api.NotFoundHandler()
+	return newBaseServer[T](&router).NotFoundHandler()
 }
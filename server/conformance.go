@@ -0,0 +1,86 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/openapi"
+	"github.com/softwareplace/goserve/security/router"
+)
+
+func (a *baseServer[T]) ConformanceCheck(strict bool) Api[T] {
+	a.conformanceCheckEnable = true
+	a.conformanceCheckStrict = strict
+	return a
+}
+
+func (a *baseServer[T]) Conformance() openapi.ConformanceReport {
+	return openapi.CheckConformance(a.swagger, a.registeredRoutes())
+}
+
+// conformanceCheck runs the spec conformance check when enabled, logging every issue found.
+// In strict mode, any issue fails the server startup.
+func (a *baseServer[T]) conformanceCheck() {
+	if !a.conformanceCheckEnable || a.swagger == nil {
+		return
+	}
+
+	report := a.Conformance()
+
+	for _, issue := range report.Issues {
+		log.Warnf("CONFORMANCE/%s: %s %s -> %s", issue.Kind, issue.Method, issue.Path, issue.Detail)
+	}
+
+	if a.conformanceCheckStrict && !report.Ok() {
+		log.Panicf("Server startup aborted: %v", report.Err())
+	}
+}
+
+func (a *baseServer[T]) registeredRoutes() []openapi.Route {
+	prefix := strings.TrimSuffix(a.contextPath, "/") + "/"
+	builtin := map[string]bool{
+		"GET::" + prefix + "health":            true,
+		"GET::" + prefix + "doc.json":          true,
+		"POST::" + prefix + "login":            true,
+		"POST::" + prefix + "api-key/generate": true,
+	}
+
+	var routes []openapi.Route
+
+	_ = a.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		// Routes without methods, such as the swagger UI prefix, are not API resources.
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		// The mock handlers of the spec operations are not implementations.
+		if strings.HasPrefix(route.GetName(), mockRoutePrefix) {
+			return nil
+		}
+
+		for _, method := range methods {
+			key := method + "::" + path
+
+			roles, _ := router.GetRolesForPath(method, path)
+
+			routes = append(routes, openapi.Route{
+				Method:  method,
+				Path:    path,
+				Roles:   roles,
+				Public:  router.IsPublicPath(method, path),
+				Builtin: builtin[key],
+			})
+		}
+		return nil
+	})
+
+	return routes
+}
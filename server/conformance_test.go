@@ -0,0 +1,92 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/openapi"
+)
+
+const conformanceSpec = `
+openapi: 3.0.3
+info:
+  title: conformance
+  version: 1.0.0
+paths:
+  /conformance/pets/{id}:
+    get:
+      security:
+        - petstore_auth:
+            - read:pets
+      responses:
+        '200':
+          description: ok
+  /conformance/orders:
+    get:
+      responses:
+        '200':
+          description: ok
+`
+
+func TestConformance(t *testing.T) {
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok(nil)
+	}
+
+	spec := func() (*openapi3.T, error) {
+		return openapi3.NewLoader().LoadFromData([]byte(conformanceSpec))
+	}
+
+	t.Run("should return no issue when every operation has a matching handler", func(t *testing.T) {
+		api := Default().
+			ContextPath("/").
+			SwaggerDocProvider(spec).
+			Get(handler, "/conformance/pets/{petId}", "read:pets").
+			PublicRouter(handler, "/conformance/orders", "GET")
+
+		require.NoError(t, api.Conformance().Err())
+	})
+
+	t.Run("should report missing and undocumented routes", func(t *testing.T) {
+		report := Default().
+			ContextPath("/").
+			SwaggerDocProvider(spec).
+			Get(handler, "/conformance/pets/{petId}", "read:pets").
+			Get(handler, "/conformance/users", "read:users").
+			Conformance()
+
+		require.Len(t, report.Issues, 2)
+		require.Equal(t, openapi.MissingHandler, report.Issues[0].Kind)
+		require.Equal(t, "/conformance/orders", report.Issues[0].Path)
+		require.Equal(t, openapi.UndocumentedRoute, report.Issues[1].Kind)
+		require.Equal(t, "/conformance/users", report.Issues[1].Path)
+	})
+
+	t.Run("should keep the real handlers registered after the mock handlers", func(t *testing.T) {
+		api := Default().
+			MockServer(true).
+			ContextPath("/").
+			SwaggerDocProvider(spec)
+		api.(*baseServer[*goservectx.DefaultContext]).mockResources()
+
+		report := api.PublicRouter(handler, "/conformance/orders", "GET").Conformance()
+
+		require.Len(t, report.Issues, 1)
+		require.Equal(t, openapi.MissingHandler, report.Issues[0].Kind)
+		require.Equal(t, "/conformance/pets/{id}", report.Issues[0].Path)
+	})
+
+	t.Run("should fail startup in strict mode", func(t *testing.T) {
+		api := Default().
+			ContextPath("/").
+			SwaggerDocProvider(spec).
+			ConformanceCheck(true)
+
+		require.Panics(t, func() {
+			api.(*baseServer[*goservectx.DefaultContext]).conformanceCheck()
+		})
+	})
+}
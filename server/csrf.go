@@ -0,0 +1,61 @@
+package server
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/security/csrf"
+	"github.com/softwareplace/goserve/security/router"
+	"github.com/softwareplace/goserve/security/session"
+)
+
+func (a *baseServer[T]) CsrfProtection(protection *csrf.Protection) Api[T] {
+	a.csrfProtection = protection
+	return a.RegisterMiddleware(a.csrfHandler, csrf.HandlerName)
+}
+
+// csrfHandler exposes the CSRF token of the request and rejects state-changing requests
+// whose origin or token does not match.
+func (a *baseServer[T]) csrfHandler(ctx *goservectx.Request[T]) (doNext bool) {
+	s, _ := session.From(ctx)
+
+	token, err := a.csrfProtection.Token(*ctx.Writer, ctx.Request, s)
+	if err != nil {
+		log.Errorf("[%s]:: CSRF/TOKEN: failed to issue token: %v", ctx.GetSessionId(), err)
+	} else if token != "" {
+		goservectx.Set(ctx, goservectx.CsrfTokenKey, token)
+		goservectx.Set(ctx, goservectx.CsrfFieldKey, a.csrfProtection.FormField())
+	}
+
+	if a.isCsrfExempt(ctx) {
+		return true
+	}
+
+	if err = a.csrfProtection.Validate(ctx.Request, s); err != nil {
+		log.Warnf("[%s]:: CSRF/VALIDATE: %s %s rejected: %v",
+			ctx.GetSessionId(),
+			ctx.Request.Method,
+			ctx.Request.URL.RequestURI(),
+			err,
+		)
+		ctx.Forbidden("Invalid CSRF token")
+		return false
+	}
+	return true
+}
+
+// isCsrfExempt reports whether the request cannot be forged by a browser or opted out of the check:
+// safe methods, requests authenticated by API key or bearer token, public paths and routes
+// configured with WithoutCsrf.
+func (a *baseServer[T]) isCsrfExempt(ctx *goservectx.Request[T]) bool {
+	if csrf.IsSafeMethod(ctx.Request.Method) || ctx.ApiKey != "" || ctx.Authorization != "" {
+		return true
+	}
+
+	if router.IsPublicPath(ctx.Request.Method, ctx.Request.URL.Path) {
+		return true
+	}
+
+	settings, ok := a.routeSettingsOf(ctx.Request)
+	return ok && settings.CsrfExempt
+}
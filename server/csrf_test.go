@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/security/csrf"
+)
+
+func TestCsrfProtection(t *testing.T) {
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok(map[string]string{"token": ctx.CsrfToken()})
+	}
+
+	api := Default().
+		ContextPath("/").
+		CsrfProtection(csrf.NewDoubleSubmit()).
+		Get(handler, "/csrf/form").
+		Post(handler, "/csrf/pets").
+		Post(handler, "/csrf/webhook").
+		RouteOptions("/csrf/webhook", "POST", WithoutCsrf()).
+		PublicRouter(handler, "/csrf/public", "POST")
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(httptest.NewRequest(http.MethodGet, "/csrf/form", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), `"token":"`)
+	tokenCookie := rr.Result().Cookies()[0]
+
+	t.Run("should reject a state-changing request without token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/csrf/pets", nil)
+		req.AddCookie(tokenCookie)
+		require.Equal(t, http.StatusForbidden, serve(req).Code)
+	})
+
+	t.Run("should accept a state-changing request with token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/csrf/pets", nil)
+		req.AddCookie(tokenCookie)
+		req.Header.Set(csrf.DefaultHeader, tokenCookie.Value)
+		require.Equal(t, http.StatusOK, serve(req).Code)
+	})
+
+	t.Run("should exempt bearer authenticated requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/csrf/pets", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		require.Equal(t, http.StatusOK, serve(req).Code)
+	})
+
+	t.Run("should exempt public paths and opted out routes", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodPost, "/csrf/public", nil)).Code)
+		require.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodPost, "/csrf/webhook", nil)).Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	goservectx "github.com/softwareplace/goserve/context"
 	goserveerror "github.com/softwareplace/goserve/error"
+	"github.com/softwareplace/goserve/view"
 )
 
 func (a *baseServer[T]) errorHandlerWrapper(next http.Handler) http.Handler {
@@ -23,9 +24,24 @@ func (a *baseServer[T]) errorHandlerWrapper(next http.Handler) http.Handler {
 func (a *baseServer[T]) onError(err error, ctx *goservectx.Request[T]) {
 	if a.errorHandler == nil {
 		log.Errorf("Error processing request: %+v", err)
-		ctx.Error("Failed to handle the request", http.StatusInternalServerError)
+		ctx.Problem(err)
 		return
 	}
 
 	a.errorHandler.Handler(ctx, err, goserveerror.HandlerWrapper)
 }
+
+func (a *baseServer[T]) ErrorFormat(format goservectx.ErrorFormat) Api[T] {
+	a.errorFormat = format
+	return a
+}
+
+func (a *baseServer[T]) Envelope(envelope *goservectx.Envelope) Api[T] {
+	a.envelope = envelope
+	return a
+}
+
+func (a *baseServer[T]) Views(engine *view.Engine) Api[T] {
+	a.views = engine
+	return a
+}
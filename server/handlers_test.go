@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	goserveerror "github.com/softwareplace/goserve/error"
+	"github.com/softwareplace/goserve/problem"
+)
+
+func TestErrorHandlerWrapper_Problems(t *testing.T) {
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		panic(problem.Validation("invalid pet", problem.FieldError{Field: "name", Message: "name is required"}))
+	}
+
+	serve := func(api Api[*goservectx.DefaultContext]) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/problems/pets", nil))
+		return rr
+	}
+
+	t.Run("should answer a panicked problem with its status and members", func(t *testing.T) {
+		rr := serve(Default().ContextPath("/").Post(handler, "/problems/pets"))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		require.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+		require.Equal(t, "urn:goserve:problem:validation", body["type"])
+		require.NotEmpty(t, body["instance"])
+		require.Len(t, body["errors"], 1)
+	})
+
+	t.Run("should answer with the default error handler", func(t *testing.T) {
+		api := Default().
+			ContextPath("/").
+			ErrorHandler(goserveerror.Default[*goservectx.DefaultContext]()).
+			ErrorFormat(goservectx.LegacyFormat).
+			Post(handler, "/problems/pets")
+
+		rr := serve(api)
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		require.Contains(t, rr.Body.String(), `"message":"invalid pet"`)
+	})
+}
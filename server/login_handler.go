@@ -7,11 +7,15 @@ import (
 	goserveerror "github.com/softwareplace/goserve/error"
 	"github.com/softwareplace/goserve/http"
 	"github.com/softwareplace/goserve/security/login"
+	"github.com/softwareplace/goserve/security/session"
 )
 
 // Login handles user login requests by processing the request body and
 // delegating to the loginDataHandler function. It ensures proper error
 // handling in cases where the request body cannot be loaded.
+//
+// By default, the login answers with a JWT. When a session manager is configured,
+// `?mode=session` starts a server-side session and answers with a session cookie instead.
 func (a *baseServer[T]) Login(ctx *goservectx.Request[T]) {
 	http.GetRequestBody(ctx, login.User{}, a.loginDataHandler, http.FailedToLoadBody[T])
 }
@@ -52,6 +56,11 @@ func (a *baseServer[T]) loginDataHandler(ctx *goservectx.Request[T], user login.
 			return
 		}
 
+		if ctx.Request.URL.Query().Get(login.ModeQueryParam) == login.SessionMode {
+			a.startSession(ctx, principal)
+			return
+		}
+
 		jwt, err := a.securityService.Generate(principal, a.loginService.TokenDuration(principal))
 
 		if err != nil {
@@ -66,3 +75,23 @@ func (a *baseServer[T]) loginDataHandler(ctx *goservectx.Request[T], user login.
 		ctx.Forbidden("Login failed: Invalid username or password")
 	})
 }
+
+func (a *baseServer[T]) startSession(ctx *goservectx.Request[T], principal T) {
+	if a.sessionManager == nil {
+		ctx.BadRequest("Login session mode is not enabled")
+		return
+	}
+
+	s, err := a.sessionManager.Start(*ctx.Writer, ctx.Request, principal.GetId(), principal.GetRoles())
+	if err != nil {
+		log.Printf("LOGIN/SESSION: Failed to start session: %v", err)
+		ctx.InternalServerError("Login failed with internal server error. Please try again later.")
+		return
+	}
+
+	response := session.Response{IssuedAt: int(s.CreatedAt.Unix())}
+	if !s.ExpiresAt.IsZero() {
+		response.Expires = int(s.ExpiresAt.Unix())
+	}
+	ctx.Ok(response)
+}
@@ -1,7 +1,6 @@
 package server
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
@@ -10,6 +9,7 @@ import (
 	goservectx "github.com/softwareplace/goserve/context"
 	"github.com/softwareplace/goserve/env"
 	goserveerror "github.com/softwareplace/goserve/error"
+	"github.com/softwareplace/goserve/problem"
 )
 
 func (a *baseServer[T]) RegisterMiddleware(middleware ApiMiddleware[T], name string) Api[T] {
@@ -24,7 +24,9 @@ func (a *baseServer[T]) RegisterMiddleware(middleware ApiMiddleware[T], name str
 	return a
 }
 
-// rootAppMiddleware logs each incoming request's method, path, and remote address
+// rootAppMiddleware logs each incoming request's method, path, and remote address.
+// It owns the request context: it creates it and releases it back to the pool once the
+// response is written, so the context must not be used after the handlers return.
 func rootAppMiddleware[T goservectx.Principal](next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var ctx *goservectx.Request[T]
@@ -47,42 +49,43 @@ func rootAppMiddleware[T goservectx.Principal](next http.Handler) http.Handler {
 			duration := time.Since(start)
 
 			if !isHelthCheckPath {
-				log.Printf("[%s]:: => request processed: %s %s in %v",
+				log.Printf("[%s]:: => request processed: %s %s -> %d (%d bytes, first byte in %v) in %v",
 					ctx.GetSessionId(),
 					r.Method,
 					uri,
+					ctx.ResponseStatus(),
+					ctx.ResponseSize(),
+					ctx.TimeToFirstByte(),
 					duration,
 				)
 			}
 
 		}, func(err error) {
+			if ctx != nil {
+				ctx.Problem(err)
+				return
+			}
 			onError(err, w)
 		})
 
 		defer func() {
-			goserveerror.Handler(ctx.Flush, func(err error) {
-				log.Errorf("Error flushing context: %v", err)
+			if ctx == nil {
+				// The context could not be created, so there is nothing to release.
+				return
+			}
+
+			goserveerror.Handler(ctx.Release, func(err error) {
+				log.Errorf("Error releasing context: %v", err)
 			})
 			ctx = nil
 		}()
 	})
 }
 
-func onError(err any, w http.ResponseWriter) {
+func onError(err error, w http.ResponseWriter) {
 	log.Errorf("Error processing request: %+v", err)
 
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Header().Set("Content-Type", "application/json")
-
-	responseBody := map[string]interface{}{
-		"message":    "Failed to process request",
-		"statusCode": http.StatusInternalServerError,
-		"timestamp":  time.Now().UnixMilli(),
-	}
-
-	err = json.NewEncoder(w).Encode(responseBody)
-
-	if err != nil {
+	if err = problem.Write(w, problem.From(err)); err != nil {
 		log.Printf("Error encoding response: %v", err)
 	}
 }
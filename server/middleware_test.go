@@ -0,0 +1,80 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+)
+
+func TestRootAppMiddleware_ReleasesContext(t *testing.T) {
+	var released *goservectx.Request[*goservectx.DefaultContext]
+	var request *http.Request
+
+	handler := rootAppMiddleware[*goservectx.DefaultContext](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		released = goservectx.Of[*goservectx.DefaultContext](w, r, "TEST")
+		request = r
+		released.Response(map[string]string{"id": released.QueryOf("id")}, http.StatusOK)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/pets?id=42", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"id":"42"}`, recorder.Body.String())
+	require.Nil(t, released.Request)
+	require.Empty(t, released.GetSessionId())
+
+	next := goservectx.Of[*goservectx.DefaultContext](httptest.NewRecorder(), request, "TEST")
+	defer next.Release()
+	require.NotEmpty(t, next.GetSessionId(), "a request kept after the response must not reach the released context")
+}
+
+func benchmarkRootAppMiddleware(b *testing.B, target string, handler func(ctx *goservectx.Request[*goservectx.DefaultContext])) {
+	output := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() {
+		log.SetOutput(output)
+	})
+
+	middleware := rootAppMiddleware[*goservectx.DefaultContext](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(goservectx.Of[*goservectx.DefaultContext](w, r, "BENCHMARK"))
+	}))
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	recorder := httptest.NewRecorder()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		middleware.ServeHTTP(recorder, request)
+	}
+}
+
+func BenchmarkRootAppMiddleware(b *testing.B) {
+	benchmarkRootAppMiddleware(b, "/pets", func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Done()
+	})
+}
+
+func BenchmarkRootAppMiddleware_Query(b *testing.B) {
+	benchmarkRootAppMiddleware(b, "/pets?status=available&limit=10", func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		_ = ctx.QueryOf("status")
+		ctx.Done()
+	})
+}
+
+func BenchmarkRootAppMiddleware_UnreadQuery(b *testing.B) {
+	benchmarkRootAppMiddleware(b, "/pets?status=available&limit=10&sort=name", func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Done()
+	})
+}
+
+func BenchmarkRootAppMiddleware_Response(b *testing.B) {
+	benchmarkRootAppMiddleware(b, "/pets/42", func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Response(map[string]string{"id": "42"}, http.StatusOK)
+	})
+}
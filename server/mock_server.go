@@ -0,0 +1,123 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	log "github.com/sirupsen/logrus"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/openapi"
+	"github.com/softwareplace/goserve/security/router"
+)
+
+// mockRoutePrefix prefixes the names of the mock routes.
+const mockRoutePrefix = "goserve-mock::"
+
+func (a *baseServer[T]) MockServer(enable bool) Api[T] {
+	a.mockServerEnable = enable
+	return a
+}
+
+// mockResources registers a mock handler for every operation of the loaded OpenAPI spec.
+// It runs once, when the server starts or serves its first request, so that any handler registered
+// before that takes precedence. Concurrent requests wait until the mock handlers are registered.
+func (a *baseServer[T]) mockResources() {
+	if !a.mockServerEnable || a.swagger == nil {
+		return
+	}
+	a.mockResourcesOnce.Do(a.registerMockResources)
+}
+
+// registerMockResources registers the mock handlers, with the access rules of their operations. The
+// operations implemented by a real handler keep the rules of that handler. The operations declaring
+// a security requirement without scopes stay protected, without roles, so only the operations
+// without any security requirement are public.
+func (a *baseServer[T]) registerMockResources() {
+	implemented := map[string]bool{}
+	for _, route := range a.registeredRoutes() {
+		implemented[openapi.RouteKey(route.Method, route.Path)] = true
+	}
+
+	for path, pathItem := range a.swagger.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			if implemented[openapi.RouteKey(method, path)] {
+				continue
+			}
+
+			handler := a.mockHandler(operation)
+
+			a.router.HandleFunc(path, func(writer http.ResponseWriter, req *http.Request) {
+				ctx := goservectx.Of[T](writer, req, "ROUTER/MOCK")
+				handler(ctx)
+			}).Methods(method).Name(mockRouteName(method, path))
+
+			if scopes := openapi.Scopes(a.swagger, operation); len(scopes) > 0 {
+				router.AddRoles(method+"::"+path, scopes...)
+			} else if !openapi.Secured(a.swagger, operation) {
+				router.AddOpenPath(method + "::" + path)
+			}
+
+			log.Debugf("MOCK %s %s", method, path)
+		}
+	}
+}
+
+// mockRouteName names the mock route of the operation, so it can be told apart from a real
+// handler registered for the same method and path.
+func mockRouteName(method string, path string) string {
+	return mockRoutePrefix + method + "::" + path
+}
+
+func (a *baseServer[T]) mockHandler(operation *openapi3.Operation) ApiContextHandler[T] {
+	return func(ctx *goservectx.Request[T]) {
+		preference := openapi.ParsePreference(ctx.HeaderOf(openapi.PreferHeader))
+
+		response, err := openapi.ResponseFor(operation, preference)
+
+		if errors.Is(err, openapi.ErrResponseNotDocumented) && preference.Code == 0 {
+			ctx.Error("Operation has no documented response", http.StatusNotImplemented)
+			return
+		}
+
+		if err != nil {
+			ctx.BadRequest(err.Error())
+			return
+		}
+
+		writer := *ctx.Writer
+
+		if preference.Code != 0 || preference.Example != "" {
+			writer.Header().Set("Preference-Applied", preferenceApplied(preference))
+		}
+
+		if response.Body == nil {
+			writer.Header().Del(goservectx.ContentType)
+			writer.WriteHeader(response.Status)
+			ctx.Done()
+			return
+		}
+
+		// The documented examples are answered as they are.
+		ctx.UseEnvelope(nil)
+		writer.Header().Set(goservectx.ContentType, response.ContentType)
+		ctx.Response(response.Body, response.Status)
+	}
+}
+
+func preferenceApplied(preference openapi.Preference) string {
+	var applied string
+	if preference.Code != 0 {
+		applied = "code=" + strconv.Itoa(preference.Code)
+	}
+
+	if preference.Example != "" {
+		if applied != "" {
+			applied += ", "
+		}
+		applied += "example=" + preference.Example
+	}
+	return applied
+}
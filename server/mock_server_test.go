@@ -0,0 +1,219 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/security/router"
+)
+
+const mockServerSpec = `
+openapi: 3.0.3
+info:
+  title: mock
+  version: 1.0.0
+paths:
+  /mock/pets/{id}:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              example:
+                id: 1
+                name: doggie
+        '404':
+          description: not found
+          content:
+            application/json:
+              examples:
+                missing:
+                  value:
+                    message: pet not found
+  /mock/orders:
+    get:
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              example:
+                - id: 1
+`
+
+func mockServerSpecProvider() (*openapi3.T, error) {
+	return openapi3.NewLoader().LoadFromData([]byte(mockServerSpec))
+}
+
+func TestMockServer_Responses(t *testing.T) {
+	api := Default().
+		MockServer(true).
+		ContextPath("/").
+		SwaggerDocProvider(mockServerSpecProvider).
+		Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			ctx.Ok([]map[string]any{{"id": 99}})
+		}, "/mock/orders")
+
+	serve := func(path string, prefer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if prefer != "" {
+			req.Header.Set("Prefer", prefer)
+		}
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("should answer with the documented example", func(t *testing.T) {
+		rr := serve("/mock/pets/1", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.JSONEq(t, `{"id":1,"name":"doggie"}`, rr.Body.String())
+	})
+
+	t.Run("should answer with the preferred response", func(t *testing.T) {
+		rr := serve("/mock/pets/1", "code=404, example=missing")
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		require.Equal(t, "code=404, example=missing", rr.Header().Get("Preference-Applied"))
+		require.JSONEq(t, `{"message":"pet not found"}`, rr.Body.String())
+	})
+
+	t.Run("should answer bad request when the preference is not documented", func(t *testing.T) {
+		rr := serve("/mock/pets/1", "code=418")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should prefer the registered handler over the mock", func(t *testing.T) {
+		rr := serve("/mock/orders", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var body []map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		require.Equal(t, float64(99), body[0]["id"])
+	})
+}
+
+func TestMockServer_Disabled(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/mock/pets/1", nil)
+	rr := httptest.NewRecorder()
+
+	Default().
+		ContextPath("/").
+		SwaggerDocProvider(mockServerSpecProvider).
+		ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMockServer_ConcurrentRequests(t *testing.T) {
+	api := Default().
+		MockServer(true).
+		ContextPath("/").
+		SwaggerDocProvider(mockServerSpecProvider)
+
+	var wait sync.WaitGroup
+	for range 8 {
+		wait.Go(func() {
+			rr := httptest.NewRecorder()
+			api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/mock/orders", nil))
+			require.Equal(t, http.StatusOK, rr.Code)
+		})
+	}
+	wait.Wait()
+}
+
+const mockAccessSpec = `
+openapi: 3.0.3
+info:
+  title: mock
+  version: 1.0.0
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: /token
+          scopes:
+            read:pets: read pets
+paths:
+  /mock-access/public:
+    get:
+      responses:
+        '204':
+          description: ok
+  /mock-access/anonymous:
+    get:
+      security:
+        - {}
+      responses:
+        '204':
+          description: ok
+  /mock-access/bearer:
+    get:
+      security:
+        - bearer: []
+      responses:
+        '204':
+          description: ok
+  /mock-access/scoped:
+    get:
+      security:
+        - oauth: [read:pets]
+      responses:
+        '204':
+          description: ok
+  /mock-access/implemented:
+    get:
+      responses:
+        '204':
+          description: ok
+`
+
+func TestMockServer_AccessRules(t *testing.T) {
+	api := Default().
+		MockServer(true).
+		ContextPath("/").
+		SwaggerDocProvider(func() (*openapi3.T, error) {
+			return openapi3.NewLoader().LoadFromData([]byte(mockAccessSpec))
+		}).
+		Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			ctx.Ok(nil)
+		}, "/mock-access/implemented", "admin")
+
+	// The first request registers the mock handlers.
+	api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	t.Run("should open the operations without security requirements", func(t *testing.T) {
+		require.True(t, router.IsPublicPath(http.MethodGet, "/mock-access/public"))
+		require.True(t, router.IsPublicPath(http.MethodGet, "/mock-access/anonymous"))
+	})
+
+	t.Run("should keep the operations secured without scopes protected", func(t *testing.T) {
+		require.False(t, router.IsPublicPath(http.MethodGet, "/mock-access/bearer"))
+		_, required := router.GetRolesForPath(http.MethodGet, "/mock-access/bearer")
+		require.False(t, required)
+	})
+
+	t.Run("should require the scopes of the secured operations", func(t *testing.T) {
+		require.False(t, router.IsPublicPath(http.MethodGet, "/mock-access/scoped"))
+		roles, _ := router.GetRolesForPath(http.MethodGet, "/mock-access/scoped")
+		require.Equal(t, []string{"read:pets"}, roles)
+	})
+
+	t.Run("should keep the access rules of the implemented operations", func(t *testing.T) {
+		require.False(t, router.IsPublicPath(http.MethodGet, "/mock-access/implemented"))
+		roles, _ := router.GetRolesForPath(http.MethodGet, "/mock-access/implemented")
+		require.Equal(t, []string{"admin"}, roles)
+	})
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/problem"
+	"github.com/softwareplace/goserve/view"
+)
+
+func TestNewWith_Options(t *testing.T) {
+	newApi := func() Api[*goservectx.DefaultContext] {
+		return NewWith[*goservectx.DefaultContext](*mux.NewRouter()).ContextPath("/")
+	}
+
+	serve := func(api Api[*goservectx.DefaultContext], method string, path string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	pet := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok(map[string]any{"id": 1, "name": "doggie"})
+	}
+
+	t.Run("should apply the request timeouts", func(t *testing.T) {
+		var deadline time.Time
+		api := newApi().
+			RequestTimeout(time.Minute).
+			Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+				deadline, _ = ctx.Context().Deadline()
+				ctx.Ok(nil)
+			}, "/new-with/deadline").
+			RouteOptions("/new-with/deadline", "GET", WithTimeout(time.Hour))
+
+		require.Equal(t, http.StatusOK, serve(api, http.MethodGet, "/new-with/deadline").Code)
+		require.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+	})
+
+	t.Run("should apply the ETag mode", func(t *testing.T) {
+		api := newApi().ETag(goservectx.ETagStrong).Get(pet, "/new-with/etag")
+
+		etag := serve(api, http.MethodGet, "/new-with/etag").Header().Get("ETag")
+		require.NotEmpty(t, etag)
+		require.Equal(t, http.StatusNotModified, serve(api, http.MethodGet, "/new-with/etag", "If-None-Match", etag).Code)
+	})
+
+	t.Run("should apply the error format", func(t *testing.T) {
+		api := newApi().
+			ErrorFormat(goservectx.LegacyFormat).
+			Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+				ctx.Problem(problem.New(http.StatusConflict, "conflict", "pet already exists"))
+			}, "/new-with/errors")
+
+		rr := serve(api, http.MethodGet, "/new-with/errors")
+		require.Equal(t, http.StatusConflict, rr.Code)
+		require.Contains(t, rr.Body.String(), `"message":"pet already exists"`)
+	})
+
+	t.Run("should apply the selectable fields", func(t *testing.T) {
+		api := newApi().Get(pet, "/new-with/fields").RouteOptions("/new-with/fields", "GET", WithFields("name"))
+
+		require.JSONEq(t, `{"name":"doggie"}`, serve(api, http.MethodGet, "/new-with/fields?fields=name").Body.String())
+		require.Equal(t, http.StatusBadRequest, serve(api, http.MethodGet, "/new-with/fields?fields=id").Code)
+	})
+
+	t.Run("should apply the envelope", func(t *testing.T) {
+		api := newApi().Envelope(&goservectx.Envelope{}).Get(pet, "/new-with/envelope")
+
+		require.JSONEq(t, `{"data":{"id":1,"name":"doggie"}}`, serve(api, http.MethodGet, "/new-with/envelope").Body.String())
+	})
+
+	t.Run("should apply the views", func(t *testing.T) {
+		engine, err := view.New(fstest.MapFS{"hello.html": {Data: []byte(`<p>{{.}}</p>`)}})
+		require.NoError(t, err)
+
+		api := newApi().Views(engine).Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			_ = ctx.HTML(http.StatusOK, "hello", "doggie")
+		}, "/new-with/views")
+
+		require.Equal(t, "<p>doggie</p>", serve(api, http.MethodGet, "/new-with/views").Body.String())
+	})
+
+	t.Run("should apply the transactions", func(t *testing.T) {
+		recorder := &recordingDriver{}
+		api := newApi().
+			Transactions(sql.OpenDB(recorder), TransactionPerRequest).
+			Post(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+				require.NotNil(t, ctx.Tx())
+				ctx.Created(nil)
+			}, "/new-with/transactions")
+
+		require.Equal(t, http.StatusCreated, serve(api, http.MethodPost, "/new-with/transactions").Code)
+		require.Equal(t, []string{"begin", "commit"}, recorder.Events())
+	})
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/env"
+)
+
+func (a *baseServer[T]) RequestTimeout(timeout time.Duration) Api[T] {
+	a.requestTimeout = timeout
+	return a
+}
+
+// requestDeadlineMiddleware applies the route or global request deadline to the request context,
+// so that handlers and outbound calls using ctx.Context() stop once it is exceeded.
+func (a *baseServer[T]) requestDeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := a.requestTimeout
+		if settings, ok := a.routeSettingsOf(r); ok && settings.Timeout > 0 {
+			timeout = settings.Timeout
+		}
+
+		if timeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		deadline, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		ctx := goservectx.Of[T](w, r.WithContext(deadline), "MIDDLEWARE/REQUEST_DEADLINE")
+		ctx.Next(next)
+
+		if errors.Is(deadline.Err(), context.DeadlineExceeded) {
+			log.Warnf("[%s]:: request deadline of %v exceeded: %s %s",
+				ctx.GetSessionId(),
+				timeout,
+				r.Method,
+				r.URL.RequestURI(),
+			)
+		}
+	})
+}
+
+func requestTimeoutFromEnv() time.Duration {
+	value := env.GetEnvOrDefault("REQUEST_TIMEOUT", "")
+	if value == "" {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		log.Errorf("REQUEST_TIMEOUT must be a valid duration, such as 30s: %v", err)
+		return 0
+	}
+	return timeout
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+)
+
+func TestRequestDeadline(t *testing.T) {
+	waitForDeadline := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		select {
+		case <-ctx.Context().Done():
+			ctx.Error(ctx.Context().Err().Error(), http.StatusServiceUnavailable)
+		case <-time.After(time.Second):
+			ctx.Ok(map[string]string{"status": "done"})
+		}
+	}
+
+	serve := func(api Api[*goservectx.DefaultContext], path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("should cancel the request context once the global deadline is exceeded", func(t *testing.T) {
+		api := Default().
+			ContextPath("/").
+			RequestTimeout(10*time.Millisecond).
+			Get(waitForDeadline, "/deadline/global")
+
+		rr := serve(api, "/deadline/global")
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		require.Contains(t, rr.Body.String(), context.DeadlineExceeded.Error())
+	})
+
+	t.Run("should apply the route deadline over the global one", func(t *testing.T) {
+		api := Default().
+			ContextPath("/").
+			RequestTimeout(time.Minute).
+			Get(waitForDeadline, "/deadline/route").
+			RouteOptions("/deadline/route", "GET", WithTimeout(10*time.Millisecond))
+
+		rr := serve(api, "/deadline/route")
+		require.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	})
+
+	t.Run("should not set a deadline when no timeout is configured", func(t *testing.T) {
+		var hasDeadline bool
+		api := Default().
+			ContextPath("/").
+			RequestTimeout(0).
+			Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+				_, hasDeadline = ctx.Context().Deadline()
+				ctx.Ok(nil)
+			}, "/deadline/none")
+
+		rr := serve(api, "/deadline/none")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.False(t, hasDeadline)
+	})
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/fieldset"
+)
+
+// RouteOption customizes the behaviour of a single route. See Api.RouteOptions.
+type RouteOption func(*RouteSettings)
+
+// RouteSettings holds the behaviour of a single route configured through RouteOption values.
+type RouteSettings struct {
+	Timeout             time.Duration       // Timeout is the request deadline of the route. It overrides the global RequestTimeout when greater than zero.
+	CsrfExempt          bool                // CsrfExempt skips the CSRF check of the route. See Api.CsrfProtection.
+	ETag                goservectx.ETagMode // ETag is the ETag mode of the JSON responses of the route. It overrides the global ETag mode when set through WithETag.
+	Fields              []string            // Fields are the member paths clients can select with ?fields= and ?exclude=. See WithFields.
+	NoEnvelope          bool                // NoEnvelope writes the responses of the route without the global envelope. See Api.Envelope.
+	Transaction         bool                // Transaction opens a transaction for the route when the scope is TransactionPerRoute. See Api.Transactions.
+	ReadOnlyTransaction bool                // ReadOnlyTransaction opens a read-only transaction for the route. See WithReadOnlyTransaction.
+	NoTransaction       bool                // NoTransaction skips the transaction of the route when the scope is TransactionPerRequest.
+	etagSet             bool
+	fieldsSet           bool
+}
+
+// WithTimeout sets the request deadline of the route, overriding the global RequestTimeout.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(settings *RouteSettings) {
+		settings.Timeout = timeout
+	}
+}
+
+// WithoutCsrf skips the CSRF check of the route, such as webhooks called by other servers.
+func WithoutCsrf() RouteOption {
+	return func(settings *RouteSettings) {
+		settings.CsrfExempt = true
+	}
+}
+
+// WithETag sets the ETag mode of the JSON responses of the route, overriding the global Api.ETag mode.
+func WithETag(mode goservectx.ETagMode) RouteOption {
+	return func(settings *RouteSettings) {
+		settings.ETag = mode
+		settings.etagSet = true
+	}
+}
+
+// WithFields lets clients trim the successful JSON responses of the route with the `?fields=` and
+// `?exclude=` query parameters, such as ?fields=id,name,owner.name or ?exclude=owner.address.
+// Paths follow the json tags and go through arrays. Requests selecting other paths than the
+// given ones, or their children, are answered 400 Bad Request. Without paths, every path is selectable.
+//
+// Example usage:
+//
+//	RouteOptions("/pets", "GET", server.WithFields("id", "name", "status", "owner"))
+func WithFields(selectable ...string) RouteOption {
+	return func(settings *RouteSettings) {
+		settings.Fields = selectable
+		settings.fieldsSet = true
+	}
+}
+
+// WithoutEnvelope writes the responses of the route without the global Api.Envelope, such as
+// routes answering a format defined by another specification.
+func WithoutEnvelope() RouteOption {
+	return func(settings *RouteSettings) {
+		settings.NoEnvelope = true
+	}
+}
+
+// WithTransaction opens a database/sql transaction for the route, when Api.Transactions uses
+// the TransactionPerRoute scope.
+func WithTransaction() RouteOption {
+	return func(settings *RouteSettings) {
+		settings.Transaction = true
+		settings.NoTransaction = false
+	}
+}
+
+// WithReadOnlyTransaction opens a read-only database/sql transaction for the route, whatever the
+// scope of Api.Transactions, such as for routes that only list or read resources. Drivers that
+// don't support read-only transactions fail to begin them.
+//
+// Example usage:
+//
+//	RouteOptions("/pets", "GET", server.WithReadOnlyTransaction())
+func WithReadOnlyTransaction() RouteOption {
+	return func(settings *RouteSettings) {
+		settings.ReadOnlyTransaction = true
+		settings.NoTransaction = false
+	}
+}
+
+// WithoutTransaction skips the database/sql transaction of the route, when Api.Transactions
+// uses the TransactionPerRequest scope, such as routes that don't use the database.
+func WithoutTransaction() RouteOption {
+	return func(settings *RouteSettings) {
+		settings.Transaction = false
+		settings.ReadOnlyTransaction = false
+		settings.NoTransaction = true
+	}
+}
+
+func (a *baseServer[T]) RouteOptions(path string, method string, options ...RouteOption) Api[T] {
+	key := method + "::" + strings.TrimSuffix(a.contextPath, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	a.routeSettingsLock.Lock()
+	defer a.routeSettingsLock.Unlock()
+
+	if a.routeSettings == nil {
+		a.routeSettings = make(map[string]*RouteSettings)
+	}
+
+	settings, ok := a.routeSettings[key]
+	if !ok {
+		settings = &RouteSettings{}
+		a.routeSettings[key] = settings
+	}
+
+	for _, option := range options {
+		option(settings)
+	}
+	return a
+}
+
+// routeSettingsOf returns the settings of the route matched for the request, if any was configured.
+func (a *baseServer[T]) routeSettingsOf(r *http.Request) (RouteSettings, bool) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return RouteSettings{}, false
+	}
+
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return RouteSettings{}, false
+	}
+
+	a.routeSettingsLock.RLock()
+	defer a.routeSettingsLock.RUnlock()
+
+	settings, ok := a.routeSettings[r.Method+"::"+path]
+	if !ok {
+		return RouteSettings{}, false
+	}
+	return *settings, true
+}
+
+func (a *baseServer[T]) ETag(mode goservectx.ETagMode) Api[T] {
+	a.etagMode = mode
+	return a
+}
+
+// routeSettingsMiddleware applies the global and route settings that the request context
+// handles by itself, such as the ETag mode of the JSON responses, the error format, the
+// envelope and the field selection.
+func (a *baseServer[T]) routeSettingsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := goservectx.Of[T](w, r, "MIDDLEWARE/ROUTE_SETTINGS")
+
+		settings, ok := a.routeSettingsOf(r)
+
+		etagMode := a.etagMode
+		if ok && settings.etagSet {
+			etagMode = settings.ETag
+		}
+		ctx.UseETag(etagMode)
+
+		if a.errorFormat != "" {
+			ctx.UseErrorFormat(a.errorFormat)
+		}
+
+		if a.envelope != nil && !(ok && settings.NoEnvelope) {
+			ctx.UseEnvelope(a.envelope)
+		}
+
+		if a.views != nil {
+			ctx.UseViews(a.views)
+		}
+
+		if ok && settings.fieldsSet {
+			selection, err := fieldset.FromQuery(r.URL.Query(), settings.Fields)
+			if err != nil {
+				ctx.Problem(err)
+				return
+			}
+			ctx.UseFieldSelection(selection)
+		}
+
+		ctx.Next(next)
+	})
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/problem"
+)
+
+func TestRouteOptions_ETag(t *testing.T) {
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok(map[string]string{"name": "doggie"})
+	}
+
+	api := Default().
+		ContextPath("/").
+		ETag(goservectx.ETagWeak).
+		Get(handler, "/etag/pets").
+		Get(handler, "/etag/orders").
+		RouteOptions("/etag/orders", "GET", WithETag(goservectx.ETagDisabled))
+
+	serve := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve("/etag/pets", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	require.Contains(t, etag, `W/"`)
+
+	require.Equal(t, http.StatusNotModified, serve("/etag/pets", etag).Code)
+
+	rr = serve("/etag/orders", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Header().Get("ETag"))
+}
+
+func TestRouteOptions_Fields(t *testing.T) {
+	type owner struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	}
+	type pet struct {
+		Id    int    `json:"id"`
+		Name  string `json:"name"`
+		Owner owner  `json:"owner"`
+	}
+
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok([]pet{{Id: 1, Name: "doggie", Owner: owner{Name: "Ana", Address: "Main"}}})
+	}
+
+	api := Default().
+		ContextPath("/").
+		Get(handler, "/fields/pets").
+		Get(handler, "/fields/all").
+		RouteOptions("/fields/pets", "GET", WithFields("id", "name", "owner.name"))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	rr := serve("/fields/pets?fields=id,owner.name")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"id": 1, "owner": {"name": "Ana"}}]`, rr.Body.String())
+
+	rr = serve("/fields/pets?fields=owner.address")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), "owner.address")
+
+	rr = serve("/fields/all?fields=id")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Body.String(), `"owner"`, "routes without WithFields ignore the parameters")
+}
+
+func TestRouteOptions_Envelope(t *testing.T) {
+	handler := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		ctx.Ok(map[string]string{"name": "doggie"})
+	}
+	failing := func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+		panic(problem.Conflict("pet already adopted"))
+	}
+
+	api := Default().
+		ContextPath("/").
+		Envelope(&goservectx.Envelope{Meta: []goservectx.MetaHook{goservectx.RequestIdMeta}}).
+		HealthResourceEnabled(true).
+		Get(handler, "/envelope/pets").
+		Get(failing, "/envelope/adopt").
+		Get(handler, "/envelope/raw").
+		RouteOptions("/envelope/raw", "GET", WithoutEnvelope())
+	api.(*baseServer[*goservectx.DefaultContext]).HealthResource()
+
+	serve := func(path string) map[string]any {
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		return body
+	}
+
+	body := serve("/envelope/pets")
+	require.Equal(t, map[string]any{"name": "doggie"}, body["data"])
+	require.NotEmpty(t, body["meta"].(map[string]any)["requestId"])
+
+	body = serve("/health")
+	require.Equal(t, map[string]any{"status": "ok"}, body["data"])
+
+	body = serve("/envelope/adopt")
+	require.Equal(t, "pet already adopted", body["errors"].([]any)[0].(map[string]any)["detail"])
+
+	body = serve("/envelope/raw")
+	require.Equal(t, map[string]any{"name": "doggie"}, body)
+}
@@ -21,7 +21,7 @@ func (a *baseServer[T]) Add(handler ApiContextHandler[T], path string, method st
 
 	a.router.HandleFunc(handlerPath, func(writer http.ResponseWriter, req *http.Request) {
 		ctx := goservectx.Of[T](writer, req, "ROUTER/HANDLER")
-		handler(ctx)
+		a.transactional(ctx, handler)
 	}).Methods(method)
 
 	router.AddRoles(method+"::"+handlerPath, requiredRoles...)
@@ -67,6 +67,8 @@ func (a *baseServer[T]) ContextPath(contextPath string) Api[T] {
 
 func (a *baseServer[T]) StartServerInGoroutine() Api[T] {
 	a.HealthResource()
+	a.conformanceCheck()
+	a.mockResources()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -100,6 +102,8 @@ func (a *baseServer[T]) StartServerInGoroutine() Api[T] {
 
 func (a *baseServer[T]) StartServer() {
 	a.HealthResource()
+	a.conformanceCheck()
+	a.mockResources()
 
 	if a.port == "" {
 		a.port = apiPort()
@@ -37,7 +37,12 @@ func (a *baseServer[T]) LoginResourceEnabled(enable bool) Api[T] {
 }
 
 func (a *baseServer[T]) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	a.HealthResource()
+	// The resources are registered on the first request only, since the router is not safe to
+	// change while it serves other requests.
+	a.serveResourcesOnce.Do(func() {
+		a.HealthResource()
+		a.mockResources()
+	})
 	a.router.ServeHTTP(w, req)
 }
 
@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 
@@ -11,9 +12,19 @@ import (
 	"github.com/softwareplace/goserve/logger"
 )
 
-func init() {
+func TestMain(m *testing.M) {
+	logDir, err := os.MkdirTemp("", "goserve-server-test")
+	if err != nil {
+		panic(err)
+	}
+
 	logger.LogReportCaller = true
+	logger.SetLogDir(logDir)
 	logger.LogSetup()
+
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
 }
 
 func TestMockServer(t *testing.T) {
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/security"
+	"github.com/softwareplace/goserve/security/session"
+)
+
+type sessionPrincipalService struct{}
+
+func (s *sessionPrincipalService) LoadPrincipal(ctx *goservectx.Request[*goservectx.DefaultContext]) bool {
+	if ctx.AccessId == "" {
+		return false
+	}
+
+	principal := goservectx.NewDefaultCtx()
+	principal.SetRequesterId(ctx.AccessId)
+	principal.SetRoles("read:pets")
+	ctx.Principal = &principal
+	return true
+}
+
+func TestSessionLogin(t *testing.T) {
+	testEnvSetup()
+	defer testEnvCleanup()
+
+	manager := session.New(session.NewMemoryStore())
+
+	api := Default().
+		ContextPath("/").
+		SessionManager(manager).
+		LoginService(loginService).
+		SecurityService(security.New[*goservectx.DefaultContext](&sessionPrincipalService{})).
+		Get(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			s, ok := session.From(ctx)
+			require.True(t, ok)
+			ctx.Ok(map[string]string{"accessId": ctx.AccessId, "session": s.PrincipalId})
+		}, "/session/pets", "read:pets")
+
+	login := func(mode string) *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"username": "my-username","password": "ynT9558iiMga&ayTVGs3Gc6ug1"}`)
+		req := httptest.NewRequest(http.MethodPost, "/login"+mode, body)
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("should answer with a session cookie in session mode", func(t *testing.T) {
+		rr := login("?mode=session")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NotContains(t, rr.Body.String(), "jwt")
+
+		cookies := rr.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, manager.CookieName(), cookies[0].Name)
+
+		req := httptest.NewRequest(http.MethodGet, "/session/pets", nil)
+		req.AddCookie(cookies[0])
+		rr = httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.JSONEq(t, `{"accessId":"081162586c7f4f77b877fbca0f09cb7f","session":"081162586c7f4f77b877fbca0f09cb7f"}`, rr.Body.String())
+	})
+
+	t.Run("should keep answering with a JWT in token mode", func(t *testing.T) {
+		rr := login("")
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Contains(t, rr.Body.String(), "jwt")
+		require.Empty(t, rr.Result().Cookies())
+	})
+
+	t.Run("should reject an invalid session cookie", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/session/pets", nil)
+		req.AddCookie(&http.Cookie{Name: manager.CookieName(), Value: "forged"})
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+// jwtOnlyService is a security.Service that does not implement security.SessionAuthorizer.
+type jwtOnlyService struct {
+	security.Service[*goservectx.DefaultContext]
+}
+
+func TestSessionManager_WithoutSessionAuthorizer(t *testing.T) {
+	testEnvSetup()
+	defer testEnvCleanup()
+
+	service := jwtOnlyService{security.New[*goservectx.DefaultContext](&sessionPrincipalService{})}
+	_, ok := any(service).(security.SessionAuthorizer[*goservectx.DefaultContext])
+	require.False(t, ok)
+
+	require.NotPanics(t, func() {
+		Default().
+			ContextPath("/").
+			SessionManager(session.New(session.NewMemoryStore())).
+			SecurityService(service)
+	})
+}
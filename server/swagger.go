@@ -73,6 +73,7 @@ func (a *baseServer[T]) SwaggerDocProvider(getSwagger func() (swagger *openapi3.
 
 	a.Router().PathPrefix(a.contextPath + "swagger/").Handler(swaggerHandler)
 
+	a.swagger = swagger
 	a.PublicRouter(a.handleSwaggerJSON(swagger), "doc.json", "GET")
 	router.AddOpenPath("GET::" + a.contextPath + "doc.json")
 	router.AddOpenPath("GET::" + a.contextPath + "swagger/.*")
@@ -93,6 +94,7 @@ func (a *baseServer[T]) SwaggerDocHandler(swaggerFile string) Api[T] {
 
 func (a *baseServer[T]) handleSwaggerJSON(swagger *openapi3.T) func(ctx *goservectx.Request[T]) {
 	return func(ctx *goservectx.Request[T]) {
+		ctx.UseEnvelope(nil)
 		ctx.Response(swagger, 200)
 	}
 }
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	goservectx "github.com/softwareplace/goserve/context"
+	"github.com/softwareplace/goserve/env"
+)
+
+// TransactionScope selects the requests that get a database/sql transaction. See Api.Transactions.
+type TransactionScope int
+
+const (
+	// TransactionPerRequest opens a transaction for every request, except the routes configured
+	// with WithoutTransaction.
+	TransactionPerRequest TransactionScope = iota
+
+	// TransactionPerRoute opens a transaction only for the routes configured with WithTransaction
+	// or WithReadOnlyTransaction.
+	TransactionPerRoute
+)
+
+func (a *baseServer[T]) Transactions(db *sql.DB, scope TransactionScope) Api[T] {
+	a.transactionDB = db
+	a.transactionScope = scope
+	return a
+}
+
+// transactional runs the route handler within the transaction of the request, if its route uses
+// one. Wrapping the route handler, rather than installing a middleware, opens the transaction after
+// every middleware, such as the security ones, so the requests they reject never begin one.
+//
+// The transaction is committed when the handler sends a status below 400, right before the headers
+// go out, so that a failed commit is still answered with a 500. Error responses and panics roll it
+// back. A panic is rolled back and then raised again, so that errorHandlerWrapper still answers it.
+func (a *baseServer[T]) transactional(ctx *goservectx.Request[T], handler ApiContextHandler[T]) {
+	if a.transactionDB == nil || ctx.Request.URL.Path == env.HealthResourcePath {
+		handler(ctx)
+		return
+	}
+
+	settings, _ := a.routeSettingsOf(ctx.Request)
+	if !a.usesTransaction(settings) {
+		handler(ctx)
+		return
+	}
+
+	tx, err := a.transactionDB.BeginTx(ctx.Context(), &sql.TxOptions{ReadOnly: settings.ReadOnlyTransaction})
+	if err != nil {
+		log.Errorf("[%s]:: TRANSACTION: failed to begin: %v", ctx.GetSessionId(), err)
+		ctx.InternalServerError("Failed to process the request")
+		return
+	}
+
+	writer := *ctx.Writer
+	txWriter := &transactionWriter[T]{ResponseWriter: goservectx.NewResponseWriter(writer), ctx: ctx, tx: tx}
+	*ctx.Writer = txWriter
+	ctx.UseTx(tx)
+
+	defer func() {
+		*ctx.Writer = writer
+		ctx.UseTx(nil)
+		if !txWriter.finished {
+			rollback(ctx, tx, "panic")
+		}
+	}()
+
+	handler(ctx)
+
+	if !txWriter.finished {
+		// The handler sent nothing, so the transaction ends as a 200 would.
+		txWriter.finish(http.StatusOK)
+	}
+}
+
+// transactionWriter ends the transaction of the request right before the headers are sent. When the
+// commit fails, it answers a 500 instead and discards the response of the handler.
+type transactionWriter[T goservectx.Principal] struct {
+	goservectx.ResponseWriter
+	ctx      *goservectx.Request[T]
+	tx       *sql.Tx
+	finished bool
+	discard  bool
+}
+
+func (w *transactionWriter[T]) WriteHeader(status int) {
+	if w.discard {
+		return
+	}
+
+	// Informational responses are sent before the final one, which ends the transaction.
+	informational := status >= 100 && status < 200 && status != http.StatusSwitchingProtocols
+	if !informational && !w.finished && !w.finish(status) {
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *transactionWriter[T]) Write(b []byte) (int, error) {
+	if !w.finished {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *transactionWriter[T]) Flush() {
+	if !w.finished {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.discard {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *transactionWriter[T]) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.finished && !w.finish(http.StatusSwitchingProtocols) {
+		return nil, nil, errors.New("transaction: failed to commit before hijacking the connection")
+	}
+	return w.ResponseWriter.Hijack()
+}
+
+// finish commits the transaction, or rolls it back for error statuses, and reports whether the
+// response of the handler can be sent. When the commit fails, it answers a 500 itself.
+func (w *transactionWriter[T]) finish(status int) bool {
+	w.finished = true
+	if status >= http.StatusBadRequest {
+		rollback(w.ctx, w.tx, "error response")
+		return true
+	}
+
+	if err := w.tx.Commit(); err != nil {
+		log.Errorf("[%s]:: TRANSACTION: failed to commit: %v", w.ctx.GetSessionId(), err)
+		// The answer goes through this writer, now finished, before it discards the handler response.
+		w.ctx.InternalServerError("Failed to process the request")
+		w.discard = true
+		return false
+	}
+	return true
+}
+
+func (a *baseServer[T]) usesTransaction(settings RouteSettings) bool {
+	if settings.NoTransaction {
+		return false
+	}
+	return a.transactionScope == TransactionPerRequest || settings.Transaction || settings.ReadOnlyTransaction
+}
+
+func rollback[T goservectx.Principal](ctx *goservectx.Request[T], tx *sql.Tx, reason string) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		log.Errorf("[%s]:: TRANSACTION: failed to roll back after %s: %v", ctx.GetSessionId(), reason, err)
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	goservectx "github.com/softwareplace/goserve/context"
+)
+
+// recordingDriver is a database/sql driver recording the transactions it begins and how they end.
+type recordingDriver struct {
+	lock          sync.Mutex
+	events        []string
+	failing       bool
+	failingCommit bool
+}
+
+func (d *recordingDriver) record(event string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *recordingDriver) Events() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]string{}, d.events...)
+}
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{d}, nil
+}
+func (d *recordingDriver) Driver() driver.Driver            { return d }
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d}, nil }
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error)           { return nil, errors.New("use BeginTx") }
+
+func (c *recordingConn) BeginTx(_ context.Context, options driver.TxOptions) (driver.Tx, error) {
+	if c.driver.failing {
+		return nil, errors.New("database is down")
+	}
+	if options.ReadOnly {
+		c.driver.record("begin read-only")
+	} else {
+		c.driver.record("begin")
+	}
+	return &recordingTx{c.driver}, nil
+}
+
+type recordingTx struct {
+	driver *recordingDriver
+}
+
+func (t *recordingTx) Commit() error {
+	t.driver.record("commit")
+	if t.driver.failingCommit {
+		return errors.New("serialization failure")
+	}
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.driver.record("rollback")
+	return nil
+}
+
+func newTransactionApi(scope TransactionScope) (Api[*goservectx.DefaultContext], *recordingDriver) {
+	recorder := &recordingDriver{}
+	db := sql.OpenDB(recorder)
+
+	withTx := func(status int) ApiContextHandler[*goservectx.DefaultContext] {
+		return func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			if ctx.Tx() == nil {
+				ctx.Response(map[string]bool{"tx": false}, status)
+				return
+			}
+			ctx.Response(map[string]bool{"tx": true}, status)
+		}
+	}
+
+	api := Default().
+		ContextPath("/").
+		Transactions(db, scope).
+		Post(withTx(http.StatusCreated), "/tx/pets").
+		Get(withTx(http.StatusOK), "/tx/pets").
+		Put(withTx(http.StatusConflict), "/tx/pets").
+		Delete(func(ctx *goservectx.Request[*goservectx.DefaultContext]) {
+			panic("failed to delete")
+		}, "/tx/pets").
+		Get(withTx(http.StatusOK), "/tx/status").
+		RouteOptions("/tx/pets", "GET", WithReadOnlyTransaction()).
+		RouteOptions("/tx/status", "GET", WithoutTransaction())
+	return api, recorder
+}
+
+// headersWriter records in the driver events when the headers of the response are sent.
+type headersWriter struct {
+	*httptest.ResponseRecorder
+	driver *recordingDriver
+}
+
+func (w *headersWriter) WriteHeader(status int) {
+	w.driver.record("headers")
+	w.ResponseRecorder.WriteHeader(status)
+}
+
+func serveTransaction(api Api[*goservectx.DefaultContext], method string, path string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest(method, path, nil))
+	return rr
+}
+
+func TestTransactions_PerRequest(t *testing.T) {
+	t.Run("should commit the successful responses", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		rr := serveTransaction(api, http.MethodPost, "/tx/pets")
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.JSONEq(t, `{"tx":true}`, rr.Body.String())
+		require.Equal(t, []string{"begin", "commit"}, recorder.Events())
+	})
+
+	t.Run("should open read-only transactions for read-only routes", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		require.Equal(t, http.StatusOK, serveTransaction(api, http.MethodGet, "/tx/pets").Code)
+		require.Equal(t, []string{"begin read-only", "commit"}, recorder.Events())
+	})
+
+	t.Run("should roll back the error responses", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		require.Equal(t, http.StatusConflict, serveTransaction(api, http.MethodPut, "/tx/pets").Code)
+		require.Equal(t, []string{"begin", "rollback"}, recorder.Events())
+	})
+
+	t.Run("should roll back the panics and still answer them", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		require.Equal(t, http.StatusInternalServerError, serveTransaction(api, http.MethodDelete, "/tx/pets").Code)
+		require.Equal(t, []string{"begin", "rollback"}, recorder.Events())
+	})
+
+	t.Run("should skip the routes without transaction", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		rr := serveTransaction(api, http.MethodGet, "/tx/status")
+		require.JSONEq(t, `{"tx":false}`, rr.Body.String())
+		require.Empty(t, recorder.Events())
+	})
+
+	t.Run("should commit before the headers are sent", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+
+		rr := httptest.NewRecorder()
+		api.ServeHTTP(&headersWriter{rr, recorder}, httptest.NewRequest(http.MethodPost, "/tx/pets", nil))
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.Equal(t, []string{"begin", "commit", "headers"}, recorder.Events())
+	})
+
+	t.Run("should answer 500 when the commit fails", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+		recorder.failingCommit = true
+
+		rr := serveTransaction(api, http.MethodPost, "/tx/pets")
+		require.Equal(t, http.StatusInternalServerError, rr.Code)
+		require.NotContains(t, rr.Body.String(), `"tx"`)
+		require.Equal(t, []string{"begin", "commit"}, recorder.Events())
+	})
+
+	t.Run("should not begin transactions for the requests rejected by the middlewares", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+		api.RegisterMiddleware(func(ctx *goservectx.Request[*goservectx.DefaultContext]) bool {
+			ctx.Error("Unauthorized", http.StatusUnauthorized)
+			return false
+		}, "reject")
+
+		require.Equal(t, http.StatusUnauthorized, serveTransaction(api, http.MethodPost, "/tx/pets").Code)
+		require.Empty(t, recorder.Events())
+	})
+
+	t.Run("should answer 500 when the transaction can't begin", func(t *testing.T) {
+		api, recorder := newTransactionApi(TransactionPerRequest)
+		recorder.failing = true
+
+		require.Equal(t, http.StatusInternalServerError, serveTransaction(api, http.MethodPost, "/tx/pets").Code)
+	})
+}
+
+func TestTransactions_PerRoute(t *testing.T) {
+	api, recorder := newTransactionApi(TransactionPerRoute)
+
+	rr := serveTransaction(api, http.MethodPost, "/tx/pets")
+	require.JSONEq(t, `{"tx":false}`, rr.Body.String())
+	require.Empty(t, recorder.Events())
+
+	require.Equal(t, http.StatusOK, serveTransaction(api, http.MethodGet, "/tx/pets").Code)
+	require.Equal(t, []string{"begin read-only", "commit"}, recorder.Events())
+}
@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/softwareplace/goserve/i18n"
+)
+
+// Validator is a long-lived validation engine built once from a ValidationSetting. It keeps the
+// go-playground struct cache, translator and registered tags between calls and is safe for
+// concurrent use, so create it once, usually at startup, and share it between requests.
+type Validator struct {
+	validate  *validator.Validate
+	trans     ut.Translator
+	separator string
+	language  string
+	arguments map[string]func(validator.FieldError) []string
+}
+
+var (
+	registryLock           sync.RWMutex
+	registeredValidations  []RValidation
+	registeredTranslations []RTranslation
+
+	sharedLock      sync.Mutex
+	sharedValidator *Validator
+)
+
+// New builds a Validator from the given setting, or from Default() when not provided. The tags and
+// translations added through RegisterValidation and RegisterTranslation are applied first, so
+// the setting can override them.
+//
+// Parameters:
+//   - setting: Optional ValidationSetting with the language, separator, translations and validators.
+//
+// Returns:
+//   - *Validator: The validator, ready for concurrent use.
+//
+// Example usage:
+//
+//	var requestValidator = validator.New()
+//
+//	func (s *Service) Create(request CreateRequest) error {
+//		if err := requestValidator.Struct(request); err != nil {
+//			return err
+//		}
+//		...
+//	}
+func New(setting ...ValidationSetting) *Validator {
+	var finalSetting ValidationSetting
+
+	if len(setting) > 0 {
+		finalSetting = setting[0]
+	} else {
+		finalSetting = *Default()
+	}
+
+	validate := validator.New()
+
+	lang := en.New()
+	uni := ut.New(lang, lang)
+	trans, _ := uni.GetTranslator(finalSetting.Language)
+
+	registryLock.RLock()
+	translations := append(append([]RTranslation{}, registeredTranslations...), finalSetting.Translations...)
+	validations := append(append([]RValidation{}, registeredValidations...), finalSetting.Validators...)
+	registryLock.RUnlock()
+
+	arguments := make(map[string]func(validator.FieldError) []string, len(translations))
+	for _, translation := range translations {
+		registerTranslation(validate, trans, translation)
+		arguments[translation.Tag] = translation.Validation
+	}
+
+	for _, vr := range validations {
+		_ = validate.RegisterValidation(vr.Tag, vr.Validator)
+	}
+
+	return &Validator{
+		validate:  validate,
+		trans:     trans,
+		separator: finalSetting.SpecSeparator,
+		language:  finalSetting.Language,
+		arguments: arguments,
+	}
+}
+
+func registerTranslation(validate *validator.Validate, trans ut.Translator, translation RTranslation) {
+	_ = validate.RegisterTranslation(translation.Tag, trans, func(ut ut.Translator) error {
+		return ut.Add(translation.Tag, translation.Message, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T(translation.Tag, translation.Validation(fe)...)
+		return t
+	})
+}
+
+// Struct validates the target struct using its validate tags, with the messages of the
+// Language of the ValidationSetting.
+//
+// Returns:
+//   - error: nil when the target is valid, a *ValidationErrors listing every failed field when
+//     one or more rules fail, or the underlying error when the target cannot be validated.
+func (v *Validator) Struct(target interface{}) error {
+	return v.StructLocale(target, v.language)
+}
+
+// StructLocale validates the target struct as Struct does, with the messages of the given locale,
+// usually selected from the Accept-Language header with i18n.Match. The messages come from the
+// validation.<tag> keys of the i18n catalog, falling back to the translations of the
+// ValidationSetting when the locale is English or has no message for the tag.
+//
+// Example usage:
+//
+//	err := validator.Shared().StructLocale(request, i18n.Match(r.Header.Get("Accept-Language")))
+func (v *Validator) StructLocale(target interface{}, locale string) error {
+	err := v.validate.Struct(target)
+
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return newValidationErrors(target, validationErrors, v.messageOf(locale), v.separator)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (v *Validator) messageOf(locale string) func(validator.FieldError) string {
+	translated := func(fe validator.FieldError) string {
+		return fe.Translate(v.trans)
+	}
+
+	if locale == "" || locale == i18n.DefaultLocale {
+		return translated
+	}
+
+	catalog := i18n.Default()
+	return func(fe validator.FieldError) string {
+		message, ok := catalog.Message(locale, i18n.ValidationPrefix+fe.Tag())
+		if !ok {
+			return translated(fe)
+		}
+
+		arguments := []string{fe.Field(), fe.Param()}
+		if argumentsOf, ok := v.arguments[fe.Tag()]; ok && argumentsOf != nil {
+			arguments = argumentsOf(fe)
+		}
+		return i18n.Format(message, arguments...)
+	}
+}
+
+// Shared returns the Validator used by StructValidation, built from Default() and the registered
+// tags on first use and rebuilt after a new tag or translation is registered.
+func Shared() *Validator {
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+
+	if sharedValidator == nil {
+		sharedValidator = New()
+	}
+	return sharedValidator
+}
+
+// RegisterValidation adds a custom tag to every Validator built afterwards, including the shared
+// one used by StructValidation. Register tags at startup, before serving requests.
+//
+// Example usage:
+//
+//	validator.RegisterValidation(validator.RValidation{
+//		Tag: "sku",
+//		Validator: func(fl validator.FieldLevel) bool {
+//			return skuPattern.MatchString(fl.Field().String())
+//		},
+//	})
+func RegisterValidation(validation RValidation) {
+	registryLock.Lock()
+	registeredValidations = append(registeredValidations, validation)
+	registryLock.Unlock()
+
+	resetShared()
+}
+
+// RegisterTranslation adds the message of a tag to every Validator built afterwards, including the
+// shared one used by StructValidation. Register translations at startup, before serving requests.
+func RegisterTranslation(translation RTranslation) {
+	registryLock.Lock()
+	registeredTranslations = append(registeredTranslations, translation)
+	registryLock.Unlock()
+
+	resetShared()
+}
+
+func resetShared() {
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+	sharedValidator = nil
+}
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+type skuRequest struct {
+	Sku string `json:"sku" validate:"required,sku"`
+}
+
+func TestValidator(t *testing.T) {
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		engine := New()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(valid bool) {
+				defer wg.Done()
+				input := TestStruct{Name: "John", Email: "john.doe@example.com", Password: "gDszOxF0xcq6nYeR6$&$5", Age: 10, Amount: 100}
+				if !valid {
+					input.Email = "invalid"
+				}
+
+				err := engine.Struct(input)
+				if valid {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, "Email must be a valid email address")
+				}
+			}(i%2 == 0)
+		}
+		wg.Wait()
+	})
+
+	t.Run("should apply the registered tags to the shared validator", func(t *testing.T) {
+		RegisterValidation(RValidation{
+			Tag: "sku",
+			Validator: func(fl validator.FieldLevel) bool {
+				return strings.HasPrefix(fl.Field().String(), "SKU-")
+			},
+		})
+		RegisterTranslation(RTranslation{
+			Tag:     "sku",
+			Message: "{0} must start with SKU-",
+			Validation: func(fe validator.FieldError) []string {
+				return []string{fe.Field()}
+			},
+		})
+
+		require.NoError(t, StructValidation(skuRequest{Sku: "SKU-1"}))
+		require.EqualError(t, StructValidation(skuRequest{Sku: "1"}), "Sku must start with SKU-")
+	})
+
+	t.Run("should use the separator of the setting", func(t *testing.T) {
+		setting := *Default()
+		setting.SpecSeparator = "; "
+
+		err := New(setting).Struct(TestStruct{Email: "invalid", Password: "gDszOxF0xcq6nYeR6$&$5", Age: 1, Amount: 11})
+		require.EqualError(t, err, "Name is a required field; Email must be a valid email address")
+	})
+}
+
+func BenchmarkStructValidation(b *testing.B) {
+	input := TestStruct{Name: "John", Email: "john.doe@example.com", Password: "gDszOxF0xcq6nYeR6$&$5", Age: 10, Amount: 100}
+
+	b.Run("shared", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = StructValidation(input)
+		}
+	})
+
+	b.Run("per call setting", func(b *testing.B) {
+		setting := *Default()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = StructValidation(input, setting)
+		}
+	})
+
+	b.Run("invalid", func(b *testing.B) {
+		invalid := input
+		invalid.Email = "invalid"
+		engine := New()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = engine.Struct(invalid)
+		}
+	})
+}
+
+func TestValidator_StructLocale(t *testing.T) {
+	engine := New()
+	input := TestStruct{Email: "invalid", Password: "gDszOxF0xcq6nYeR6$&$5", Age: 1, Amount: 11}
+
+	err := engine.StructLocale(input, "pt-BR")
+	require.EqualError(t, err, "Name é um campo obrigatório\nEmail deve ser um endereço de e-mail válido")
+
+	err = engine.StructLocale(TestStruct{Name: "John has a long name that exceeds", Email: "a@b.co", Password: "gDszOxF0xcq6nYeR6$&$5", Age: 1, Amount: 11}, "fr")
+	require.EqualError(t, err, "Name doit contenir au plus 20 caractères")
+
+	err = engine.StructLocale(input, "en")
+	require.EqualError(t, err, "Name is a required field\nEmail must be a valid email address")
+}
@@ -0,0 +1,158 @@
+package validator
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/softwareplace/goserve/problem"
+)
+
+// Sources of a validated field, read from the query, path, header, cookie and form struct tags.
+// Fields without any of these tags belong to the request body.
+const (
+	SourceQuery  = "query"
+	SourcePath   = "path"
+	SourceHeader = "header"
+	SourceCookie = "cookie"
+	SourceForm   = "form"
+	SourceBody   = "body"
+)
+
+var sourceTags = []string{SourceQuery, SourcePath, SourceHeader, SourceCookie, SourceForm}
+
+// FieldError describes a single failed validation rule.
+type FieldError struct {
+	Field   string `json:"field"`           // Field is the JSON path of the field, such as address.street or items[0].name, or the parameter name for query, path, header, cookie and form fields.
+	Tag     string `json:"tag"`             // Tag is the failed validation tag, such as required or max.
+	Param   string `json:"param,omitempty"` // Param is the parameter of the tag, such as 20 in max=20.
+	Source  string `json:"source"`          // Source is where the field came from: query, path, header, cookie, form or body.
+	Message string `json:"message"`         // Message is the translated, human-readable message.
+}
+
+// ValidationErrors is returned by StructValidation when one or more validation rules fail.
+// Error joins the translated messages with the SpecSeparator of the ValidationSetting, so
+// callers that only log or display the error keep working, while the HTTP layer can render
+// Fields as an array.
+type ValidationErrors struct {
+	Fields    []FieldError `json:"errors"`
+	separator string
+}
+
+// Error implements the error interface
+func (e *ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, e.separator)
+}
+
+// Problem converts the validation errors into a 422 problem listing every failed field in the
+// errors member, so ctx.Problem and the default error handler render them as an array.
+func (e *ValidationErrors) Problem() *problem.Problem {
+	return problem.New(http.StatusUnprocessableEntity, "validation", "The request has invalid fields").
+		With("errors", e.Fields).
+		Wrap(e)
+}
+
+func newValidationErrors(
+	target interface{},
+	errs validator.ValidationErrors,
+	message func(validator.FieldError) string,
+	separator string,
+) *ValidationErrors {
+	targetType := reflect.TypeOf(target)
+
+	fields := make([]FieldError, 0, len(errs))
+	for _, e := range errs {
+		path, source := fieldPath(targetType, e.StructNamespace())
+		fields = append(fields, FieldError{
+			Field:   path,
+			Tag:     e.Tag(),
+			Param:   e.Param(),
+			Source:  source,
+			Message: strings.Trim(message(e), " "),
+		})
+	}
+
+	return &ValidationErrors{Fields: fields, separator: separator}
+}
+
+// fieldPath converts a struct namespace, such as Order.Items[0].Name, into the JSON path of the
+// field, such as items[0].name, and finds its source from the tags of the top level field.
+func fieldPath(targetType reflect.Type, namespace string) (string, string) {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		// The first segment is the name of the validated struct.
+		segments = segments[1:]
+	}
+
+	source := SourceBody
+	currentType := targetType
+	path := make([]string, 0, len(segments))
+
+	for i, segment := range segments {
+		name, index := segment, ""
+		if bracket := strings.IndexByte(segment, '['); bracket >= 0 {
+			name, index = segment[:bracket], segment[bracket:]
+		}
+
+		currentType = indirect(currentType)
+		if currentType == nil || currentType.Kind() != reflect.Struct {
+			path = append(path, segment)
+			continue
+		}
+
+		field, ok := currentType.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			currentType = nil
+			continue
+		}
+
+		jsonName := jsonNameOf(field)
+		if i == 0 {
+			for _, tag := range sourceTags {
+				if paramName, ok := field.Tag.Lookup(tag); ok {
+					source = tag
+					if paramName != "" {
+						jsonName = paramName
+					}
+					break
+				}
+			}
+		}
+
+		path = append(path, jsonName+index)
+
+		currentType = field.Type
+		for range strings.Count(index, "[") {
+			currentType = indirect(currentType)
+			if currentType != nil && (currentType.Kind() == reflect.Slice ||
+				currentType.Kind() == reflect.Array ||
+				currentType.Kind() == reflect.Map) {
+				currentType = currentType.Elem()
+			}
+		}
+	}
+
+	return strings.Join(path, "."), source
+}
+
+func jsonNameOf(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,409 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/softwareplace/goserve/env"
+	"github.com/softwareplace/goserve/problem"
+)
+
+// Rules of a PasswordPolicy, reported in PasswordViolation.Rule.
+const (
+	PasswordMinLength  = "min_length"
+	PasswordMaxLength  = "max_length"
+	PasswordUpper      = "upper"
+	PasswordLower      = "lower"
+	PasswordDigit      = "digit"
+	PasswordSpecial    = "special"
+	PasswordRepeated   = "repeated"
+	PasswordBanned     = "banned"
+	PasswordUsername   = "username"
+	PasswordLowEntropy = "entropy"
+)
+
+const (
+	passwordSpecialSet       = `!@#$%^&*()-_=+{}[]|;:'",.<>/?`
+	defaultPasswordMinLength = 8
+	minUsernameOverlap       = 3
+)
+
+// PasswordPolicy describes the rules a password must follow. The zero value of a rule disables it,
+// so a policy only checks what it configures. DefaultPasswordPolicy reads the policy from the
+// environment, and SetPasswordPolicy makes it the policy of the `password` validate tag.
+type PasswordPolicy struct {
+	MinLength      int     // MinLength is the minimum number of characters.
+	MaxLength      int     // MaxLength is the maximum number of characters.
+	RequireUpper   bool    // RequireUpper requires at least one uppercase letter.
+	RequireLower   bool    // RequireLower requires at least one lowercase letter.
+	RequireDigit   bool    // RequireDigit requires at least one number.
+	RequireSpecial bool    // RequireSpecial requires at least one special character, such as !@#$%.
+	MaxRepeated    int     // MaxRepeated is the maximum number of consecutive identical characters.
+	MinEntropy     float64 // MinEntropy is the minimum entropy score, in bits. See PasswordEntropy.
+	CheckUsername  bool    // CheckUsername rejects passwords containing the username, or contained in it.
+	banned         map[string]struct{}
+}
+
+// PasswordViolation is a rule of the PasswordPolicy the password does not follow.
+type PasswordViolation struct {
+	Rule    string `json:"rule"`    // Rule is the failed rule, such as min_length or banned.
+	Message string `json:"message"` // Message is the human-readable reason.
+}
+
+// PasswordError lists every rule of the PasswordPolicy the password does not follow.
+type PasswordError struct {
+	Violations []PasswordViolation `json:"errors"`
+}
+
+// Error implements the error interface
+func (e *PasswordError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "password " + strings.Join(messages, ", ")
+}
+
+// Problem converts the password error into a 422 problem listing every failed rule in the errors member.
+func (e *PasswordError) Problem() *problem.Problem {
+	return problem.New(http.StatusUnprocessableEntity, "password-policy", "The password does not meet the password policy").
+		With("errors", e.Violations).
+		Wrap(e)
+}
+
+var (
+	passwordPolicyLock sync.RWMutex
+	passwordPolicy     *PasswordPolicy
+)
+
+// DefaultPasswordPolicy returns the policy configured by the environment:
+//
+//   - PASSWORD_MIN_LENGTH: Minimum length, 8 by default.
+//   - PASSWORD_MAX_LENGTH: Maximum length, unlimited by default.
+//   - PASSWORD_REQUIRE_UPPER, PASSWORD_REQUIRE_LOWER, PASSWORD_REQUIRE_DIGIT, PASSWORD_REQUIRE_SPECIAL:
+//     Required character classes, all true by default.
+//   - PASSWORD_MAX_REPEATED: Maximum consecutive identical characters, unlimited by default.
+//   - PASSWORD_MIN_ENTROPY: Minimum entropy score in bits, disabled by default.
+//   - PASSWORD_CHECK_USERNAME: Reject passwords similar to the username, false by default.
+//   - PASSWORD_BANNED_FILE: File with one banned password per line.
+func DefaultPasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:      env.GetIntEnvOrElseDefault("PASSWORD_MIN_LENGTH", defaultPasswordMinLength),
+		MaxLength:      env.GetIntEnvOrElseDefault("PASSWORD_MAX_LENGTH", 0),
+		RequireUpper:   env.GetBoolEnvOrDefault("PASSWORD_REQUIRE_UPPER", true),
+		RequireLower:   env.GetBoolEnvOrDefault("PASSWORD_REQUIRE_LOWER", true),
+		RequireDigit:   env.GetBoolEnvOrDefault("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSpecial: env.GetBoolEnvOrDefault("PASSWORD_REQUIRE_SPECIAL", true),
+		MaxRepeated:    env.GetIntEnvOrElseDefault("PASSWORD_MAX_REPEATED", 0),
+		CheckUsername:  env.GetBoolEnvOrDefault("PASSWORD_CHECK_USERNAME", false),
+	}
+
+	if value := env.GetEnvOrDefault("PASSWORD_MIN_ENTROPY", ""); value != "" {
+		entropy, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Warnf("Failed to parse PASSWORD_MIN_ENTROPY as float: %v", err)
+		}
+		policy.MinEntropy = entropy
+	}
+
+	if path := env.GetEnvOrDefault("PASSWORD_BANNED_FILE", ""); path != "" {
+		if err := policy.LoadBannedPasswords(path); err != nil {
+			log.Errorf("Failed to load PASSWORD_BANNED_FILE %s: %v", path, err)
+		}
+	}
+	return policy
+}
+
+// SetPasswordPolicy sets the policy checked by the `password` validate tag and by CheckPassword.
+// Set it at startup, before serving requests.
+//
+// Example usage:
+//
+//	policy := validator.DefaultPasswordPolicy()
+//	policy.MinLength = 12
+//	policy.CheckUsername = true
+//	_ = policy.LoadBannedPasswords("config/banned-passwords.txt")
+//	validator.SetPasswordPolicy(policy)
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicyLock.Lock()
+	passwordPolicy = &policy
+	passwordPolicyLock.Unlock()
+
+	// The English message of the password tag describes the policy.
+	resetShared()
+}
+
+// ActivePasswordPolicy returns the policy set by SetPasswordPolicy, or DefaultPasswordPolicy.
+func ActivePasswordPolicy() PasswordPolicy {
+	passwordPolicyLock.RLock()
+	policy := passwordPolicy
+	passwordPolicyLock.RUnlock()
+
+	if policy != nil {
+		return *policy
+	}
+
+	passwordPolicyLock.Lock()
+	defer passwordPolicyLock.Unlock()
+	if passwordPolicy == nil {
+		defaultPolicy := DefaultPasswordPolicy()
+		passwordPolicy = &defaultPolicy
+	}
+	return *passwordPolicy
+}
+
+// CheckPassword checks the password against the active PasswordPolicy, for registration or
+// password change flows that need the detailed reasons.
+//
+// Parameters:
+//   - password: The password to check.
+//   - username: The username, or email, of the user, used by the CheckUsername rule. It may be empty.
+//
+// Returns:
+//   - error: nil when the password follows the policy, or a *PasswordError listing every failed rule,
+//     which ctx.Problem answers as a 422 problem.
+func CheckPassword(password string, username string) error {
+	policy := ActivePasswordPolicy()
+	return policy.Check(password, username)
+}
+
+// LoadBannedPasswords adds the passwords of the file, one per line, to the banned list of the
+// policy. Blank lines and lines starting with # are ignored, and the comparison ignores case.
+func (p *PasswordPolicy) LoadBannedPasswords(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var passwords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			passwords = append(passwords, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	p.Ban(passwords...)
+	return nil
+}
+
+// Ban adds the passwords to the banned list of the policy. The comparison ignores case.
+func (p *PasswordPolicy) Ban(passwords ...string) {
+	banned := make(map[string]struct{}, len(p.banned)+len(passwords))
+	for password := range p.banned {
+		banned[password] = struct{}{}
+	}
+	for _, password := range passwords {
+		banned[strings.ToLower(password)] = struct{}{}
+	}
+	p.banned = banned
+}
+
+// Check checks the password against every rule of the policy.
+//
+// Returns:
+//   - error: nil when the password follows the policy, or a *PasswordError listing every failed rule.
+func (p PasswordPolicy) Check(password string, username string) error {
+	var violations []PasswordViolation
+	fail := func(rule string, message string) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: message})
+	}
+
+	length := len([]rune(password))
+	if p.MinLength > 0 && length < p.MinLength {
+		fail(PasswordMinLength, fmt.Sprintf("must have at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		fail(PasswordMaxLength, fmt.Sprintf("must have at most %d characters", p.MaxLength))
+	}
+
+	classes := classesOf(password)
+	if p.RequireUpper && !classes.upper {
+		fail(PasswordUpper, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !classes.lower {
+		fail(PasswordLower, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !classes.digit {
+		fail(PasswordDigit, "must contain a number")
+	}
+	if p.RequireSpecial && !classes.special {
+		fail(PasswordSpecial, "must contain a special character")
+	}
+
+	if p.MaxRepeated > 0 && maxRepeated(password) > p.MaxRepeated {
+		fail(PasswordRepeated, fmt.Sprintf("must not repeat the same character more than %d times in a row", p.MaxRepeated))
+	}
+
+	if _, ok := p.banned[strings.ToLower(password)]; ok {
+		fail(PasswordBanned, "is too common")
+	}
+
+	if p.CheckUsername && similarToUsername(password, username) {
+		fail(PasswordUsername, "must not contain the username")
+	}
+
+	if p.MinEntropy > 0 && PasswordEntropy(password) < p.MinEntropy {
+		fail(PasswordLowEntropy, "is too easy to guess")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordError{Violations: violations}
+	}
+	return nil
+}
+
+// Describe returns the requirements of the policy, such as "8 characters, 1 uppercase, 1 lowercase,
+// 1 number, and 1 special character", used by the English message of the password tag.
+func (p PasswordPolicy) Describe() string {
+	var requirements []string
+	if p.MinLength > 0 {
+		requirements = append(requirements, fmt.Sprintf("%d characters", p.MinLength))
+	}
+	if p.RequireUpper {
+		requirements = append(requirements, "1 uppercase")
+	}
+	if p.RequireLower {
+		requirements = append(requirements, "1 lowercase")
+	}
+	if p.RequireDigit {
+		requirements = append(requirements, "1 number")
+	}
+	if p.RequireSpecial {
+		requirements = append(requirements, "1 special character")
+	}
+
+	if len(requirements) > 1 {
+		last := len(requirements) - 1
+		return strings.Join(requirements[:last], ", ") + ", and " + requirements[last]
+	}
+	return strings.Join(requirements, "")
+}
+
+// Validation returns the `password` validate tag checking the policy. The tag parameter names the
+// sibling field holding the username, for the CheckUsername rule, such as `validate:"password=Username"`.
+func (p PasswordPolicy) Validation() RValidation {
+	return RValidation{
+		Tag: "password",
+		Validator: func(fl validator.FieldLevel) bool {
+			username := ""
+			if name := fl.Param(); name != "" {
+				parent := fl.Parent()
+				for parent.Kind() == reflect.Ptr {
+					parent = parent.Elem()
+				}
+				if field := parent.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+					username = field.String()
+				}
+			}
+			return p.Check(fl.Field().String(), username) == nil
+		},
+	}
+}
+
+// PasswordEntropy returns the entropy score of the password, in bits: its length times the base 2
+// logarithm of the size of the character classes it uses. It does not detect dictionary words,
+// so combine it with the banned list.
+func PasswordEntropy(password string) float64 {
+	classes := classesOf(password)
+
+	pool := 0
+	if classes.lower {
+		pool += 26
+	}
+	if classes.upper {
+		pool += 26
+	}
+	if classes.digit {
+		pool += 10
+	}
+	if classes.special {
+		pool += len(passwordSpecialSet)
+	}
+	if classes.other {
+		pool += 100
+	}
+
+	if pool == 0 {
+		return 0
+	}
+	return float64(len([]rune(password))) * math.Log2(float64(pool))
+}
+
+type characterClasses struct {
+	upper, lower, digit, special, other bool
+}
+
+func classesOf(password string) characterClasses {
+	var classes characterClasses
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			classes.upper = true
+		case unicode.IsLower(r):
+			classes.lower = true
+		case unicode.IsDigit(r):
+			classes.digit = true
+		case strings.ContainsRune(passwordSpecialSet, r):
+			classes.special = true
+		default:
+			classes.other = true
+		}
+	}
+	return classes
+}
+
+func maxRepeated(password string) int {
+	longest, current := 0, 0
+	var previous rune
+	for i, r := range []rune(password) {
+		if i > 0 && r == previous {
+			current++
+		} else {
+			current = 1
+		}
+		previous = r
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+func similarToUsername(password string, username string) bool {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if at := strings.IndexByte(username, '@'); at > 0 {
+		// Compare with the local part of emails.
+		username = username[:at]
+	}
+	if len(username) < minUsernameOverlap {
+		return false
+	}
+
+	password = strings.ToLower(password)
+	return strings.Contains(password, username) ||
+		strings.Contains(reverse(password), username) ||
+		(len(password) >= minUsernameOverlap && strings.Contains(username, password))
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type registration struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required,password=Username"`
+}
+
+func rulesOf(t *testing.T, err error) []string {
+	t.Helper()
+
+	var passwordError *PasswordError
+	require.ErrorAs(t, err, &passwordError)
+
+	rules := make([]string, 0, len(passwordError.Violations))
+	for _, violation := range passwordError.Violations {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Run("should keep the default rules", func(t *testing.T) {
+		policy := DefaultPasswordPolicy()
+
+		require.NoError(t, policy.Check("gDszOxF0xcq6nYeR6$&$5", ""))
+		require.Equal(t, []string{PasswordMinLength, PasswordUpper, PasswordDigit, PasswordSpecial}, rulesOf(t, policy.Check("secret", "")))
+		require.Equal(t, "8 characters, 1 uppercase, 1 lowercase, 1 number, and 1 special character", policy.Describe())
+	})
+
+	t.Run("should read the policy from the environment", func(t *testing.T) {
+		banned := filepath.Join(t.TempDir(), "banned.txt")
+		require.NoError(t, os.WriteFile(banned, []byte("# common passwords\nPassw0rd!\n\nqwerty\n"), 0o600))
+
+		t.Setenv("PASSWORD_MIN_LENGTH", "12")
+		t.Setenv("PASSWORD_MAX_LENGTH", "16")
+		t.Setenv("PASSWORD_REQUIRE_SPECIAL", "false")
+		t.Setenv("PASSWORD_BANNED_FILE", banned)
+
+		policy := DefaultPasswordPolicy()
+		require.Equal(t, 12, policy.MinLength)
+		require.Equal(t, 16, policy.MaxLength)
+		require.False(t, policy.RequireSpecial)
+
+		require.Equal(t, []string{PasswordMinLength, PasswordBanned}, rulesOf(t, policy.Check("passw0RD!", "")))
+		require.Equal(t, []string{PasswordMaxLength}, rulesOf(t, policy.Check("Abcdefgh12345678x", "")))
+	})
+
+	t.Run("should check repeated characters, username and entropy", func(t *testing.T) {
+		policy := PasswordPolicy{MaxRepeated: 2, CheckUsername: true, MinEntropy: 60}
+
+		require.Equal(t, []string{PasswordRepeated, PasswordUsername, PasswordLowEntropy},
+			rulesOf(t, policy.Check("joaooo", "Joao")))
+		require.Equal(t, []string{PasswordUsername}, rulesOf(t, policy.Check("x-AIRAM-42-zyx", "maria@example.com")))
+		require.NoError(t, policy.Check("correct-Horse-battery-9", "maria@example.com"))
+	})
+
+	t.Run("should report detailed reasons as a problem", func(t *testing.T) {
+		err := PasswordPolicy{MinLength: 10, RequireDigit: true}.Check("short", "")
+
+		p := err.(*PasswordError).Problem()
+		require.Equal(t, 422, p.Status)
+		require.EqualError(t, err, "password must have at least 10 characters, must contain a number")
+	})
+
+	t.Run("should plug into the password tag", func(t *testing.T) {
+		defer SetPasswordPolicy(DefaultPasswordPolicy())
+
+		policy := DefaultPasswordPolicy()
+		policy.MinLength = 10
+		policy.CheckUsername = true
+		SetPasswordPolicy(policy)
+
+		require.NoError(t, StructValidation(registration{Username: "maria", Password: "Str0ng&Secret"}))
+		require.EqualError(t, StructValidation(registration{Username: "maria", Password: "Maria&2024!"}),
+			"Password must contain at least: 10 characters, 1 uppercase, 1 lowercase, 1 number, and 1 special character")
+		require.Error(t, CheckPassword("Str0ng&", "maria"))
+	})
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	require.Zero(t, PasswordEntropy(""))
+	require.InDelta(t, 37.6, PasswordEntropy("password"), 0.1)
+	require.Greater(t, PasswordEntropy("gDszOxF0xcq6nYeR6$&$5"), PasswordEntropy("gDszOxF0xcq6nYeR"))
+}
+
+func TestPasswordPolicy_LocalizedMessage(t *testing.T) {
+	defer SetPasswordPolicy(DefaultPasswordPolicy())
+
+	policy := DefaultPasswordPolicy()
+	policy.MinLength = 12
+	policy.RequireSpecial = false
+	SetPasswordPolicy(policy)
+
+	err := Shared().StructLocale(registration{Username: "maria", Password: "short"}, "pt-BR")
+	require.EqualError(t, err, "Password deve conter pelo menos: "+policy.Describe())
+	require.Contains(t, err.Error(), "12 characters")
+	require.NotContains(t, err.Error(), "special character")
+}
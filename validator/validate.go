@@ -1,12 +1,6 @@
 package validator
 
 import (
-	"errors"
-	"regexp"
-	"strings"
-
-	"github.com/go-playground/locales/en"
-	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -28,21 +22,10 @@ type ValidationSetting struct {
 	Validators    []RValidation
 }
 
+// DefaultPasswordValidations returns the `password` validate tag checking the active PasswordPolicy.
+// See SetPasswordPolicy.
 func DefaultPasswordValidations() RValidation {
-	return RValidation{
-		Tag: "password",
-		Validator: func(fl validator.FieldLevel) bool {
-			password := fl.Field().String()
-			if len(password) < 8 {
-				return false
-			}
-			hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-			hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-			hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
-			hasSpecial := regexp.MustCompile(`[!@#$%^&*()\-_=+{}\[\]|;:'",.<>/?]`).MatchString(password)
-			return hasLower && hasUpper && hasNumber && hasSpecial
-		},
-	}
+	return ActivePasswordPolicy().Validation()
 }
 
 func DefaultTranslations() []RTranslation {
@@ -105,14 +88,23 @@ func DefaultTranslations() []RTranslation {
 		},
 		{
 			Tag:     "password",
-			Message: "{0} must contain at least: 8 characters, 1 uppercase, 1 lowercase, 1 number, and 1 special character",
+			Message: passwordMessage(),
 			Validation: func(fe validator.FieldError) []string {
-				return []string{fe.Field()}
+				// The requirements of the active policy are the {1} of the localized messages.
+				return []string{fe.Field(), ActivePasswordPolicy().Describe()}
 			},
 		},
 	}
 }
 
+func passwordMessage() string {
+	requirements := ActivePasswordPolicy().Describe()
+	if requirements == "" {
+		return "{0} does not meet the password policy"
+	}
+	return "{0} must contain at least: " + requirements
+}
+
 func Default() *ValidationSetting {
 	return &ValidationSetting{
 		Language:      "en",
@@ -124,53 +116,31 @@ func Default() *ValidationSetting {
 	}
 }
 
+// StructValidation validates the target struct using its validate tags.
+//
+// Parameters:
+//   - target: The struct, or pointer to struct, to validate.
+//   - setting: Optional ValidationSetting. Default() is used when not provided.
+//
+// Returns:
+//   - error: nil when the target is valid, a *ValidationErrors listing every failed field when
+//     one or more rules fail, or the underlying error when the target cannot be validated.
+//
+// Example usage:
+//
+//	var validationErrors *validator.ValidationErrors
+//	if err := validator.StructValidation(request); errors.As(err, &validationErrors) {
+//		for _, field := range validationErrors.Fields {
+//			log.Printf("%s (%s): %s", field.Field, field.Source, field.Message)
+//		}
+//	}
+//
+// StructValidation uses a shared Validator built once from Default() and the registered tags,
+// so its struct cache is reused between calls. When a setting is given, a new Validator is built
+// for the call; create it once with New and reuse it instead when validating on hot paths.
 func StructValidation(target interface{}, setting ...ValidationSetting) error {
-	var finalSetting ValidationSetting
-
 	if len(setting) > 0 {
-		finalSetting = setting[0]
-	} else {
-		finalSetting = *Default()
-	}
-
-	validate := validator.New()
-
-	lang := en.New()
-	uni := ut.New(lang, lang)
-	trans, _ := uni.GetTranslator(finalSetting.Language)
-
-	registerTranslation := func(tag string, message string, customFunc func(validator.FieldError) []string) {
-		_ = validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
-			return ut.Add(tag, message, true)
-		}, func(ut ut.Translator, fe validator.FieldError) string {
-			t, _ := ut.T(tag, customFunc(fe)...)
-			return t
-		})
-	}
-
-	for _, translation := range finalSetting.Translations {
-		registerTranslation(translation.Tag, translation.Message, translation.Validation)
-	}
-
-	for _, vr := range finalSetting.Validators {
-		_ = validate.RegisterValidation(vr.Tag, vr.Validator)
+		return New(setting[0]).Struct(target)
 	}
-
-	err := validate.Struct(target)
-
-	if err != nil {
-		var validationErrors validator.ValidationErrors
-		if errors.As(err, &validationErrors) {
-			var errorMessages []string
-			for _, e := range validationErrors {
-				translatedMessage := e.Translate(trans)
-				translatedMessage = strings.Trim(translatedMessage, " ")
-				errorMessages = append(errorMessages, translatedMessage)
-			}
-			return errors.New(strings.Join(errorMessages, finalSetting.SpecSeparator))
-		}
-		return err
-	}
-
-	return nil
+	return Shared().Struct(target)
 }
@@ -150,3 +150,45 @@ func TestStructValidation(t *testing.T) {
 		})
 	}
 }
+
+type address struct {
+	Street string `json:"street" validate:"required"`
+}
+
+type item struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type orderRequest struct {
+	Tenant   string   `header:"X-Tenant" validate:"required"`
+	Page     int      `query:"page" json:"page" validate:"gte=1"`
+	Customer string   `json:"customer" validate:"required,min=3"`
+	Address  *address `json:"address" validate:"required"`
+	Items    []item   `json:"items" validate:"dive"`
+}
+
+func TestStructValidation_FieldErrors(t *testing.T) {
+	err := StructValidation(&orderRequest{
+		Page:     0,
+		Customer: "Jo",
+		Address:  &address{},
+		Items:    []item{{Name: "pen"}, {}},
+	})
+
+	var validationErrors *ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+	require.Equal(t, []FieldError{
+		{Field: "X-Tenant", Tag: "required", Source: SourceHeader, Message: "Tenant is a required field"},
+		{Field: "page", Tag: "gte", Param: "1", Source: SourceQuery, Message: "Page must be greater or equal to 1"},
+		{Field: "customer", Tag: "min", Param: "3", Source: SourceBody, Message: "Customer must be at least 3 characters"},
+		{Field: "address.street", Tag: "required", Source: SourceBody, Message: "Street is a required field"},
+		{Field: "items[1].name", Tag: "required", Source: SourceBody, Message: "Name is a required field"},
+	}, validationErrors.Fields)
+
+	require.Equal(t, "Tenant is a required field\nPage must be greater or equal to 1\n"+
+		"Customer must be at least 3 characters\nStreet is a required field\nName is a required field", err.Error())
+
+	p := validationErrors.Problem()
+	require.Equal(t, 422, p.Status)
+	require.Len(t, p.Extensions["errors"], 5)
+}
@@ -0,0 +1,273 @@
+// Package view renders server-side HTML pages with html/template, for simple pages such as the ones
+// of admin tools. Templates are loaded from an fs.FS, usually embedded, with shared layouts and
+// partials, and can be reloaded on every render during development.
+package view
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/softwareplace/goserve/env"
+)
+
+const (
+	// ContentType is the content type of the rendered pages.
+	ContentType = "text/html; charset=utf-8"
+
+	// DefaultCsrfField is the form field of the CSRF token written by the csrfField template func.
+	DefaultCsrfField = "csrf_token"
+)
+
+// Options customizes how an Engine loads its templates.
+type Options struct {
+	Extension string           // Extension of the template files. Defaults to .html.
+	Layouts   string           // Layouts is the directory of the layout templates, shared by every page. Defaults to layouts.
+	Partials  string           // Partials is the directory of the partial templates, shared by every page. Defaults to partials.
+	Funcs     template.FuncMap // Funcs are additional template funcs, available to every template.
+	Reload    bool             // Reload parses the templates again on every render, to see changes without restarting in development.
+}
+
+// Request holds the values of the request exposed to the templates through the principal,
+// csrfToken, csrfField, locale and t funcs.
+type Request struct {
+	Principal any                                     // Principal is the authenticated principal, or nil.
+	CsrfToken string                                  // CsrfToken is the CSRF token of the request, or empty when CSRF protection is disabled.
+	CsrfField string                                  // CsrfField is the form field of the CSRF token. Defaults to DefaultCsrfField.
+	Locale    string                                  // Locale is the locale of the request.
+	Translate func(key string, args ...string) string // Translate translates a message key into the locale of the request.
+}
+
+// Engine renders the HTML templates of an fs.FS. Each page is parsed with the layouts and
+// partials into its own template set, so pages can define the blocks of a layout, such as
+// content, without clashing with each other. Templates are named after their path without the
+// extension, such as users/list or layouts/base.
+//
+// An Engine is safe for concurrent use.
+type Engine struct {
+	fsys    fs.FS
+	options Options
+
+	lock   sync.RWMutex
+	shared *page
+	pages  map[string]*page
+}
+
+// page is a parsed template set. It is never executed: renders execute its clones instead, whose
+// funcs are bound to the request they render. A clone is escaped on its first render and then
+// kept in the pool, so later renders reuse it.
+type page struct {
+	set  *template.Template
+	pool sync.Pool
+}
+
+// binding is a clone of a page set, rendering one request at a time.
+type binding struct {
+	set     *template.Template
+	request Request
+}
+
+// New creates an Engine loading the templates of fsys and parses them, so template errors are
+// found at startup. Reload is also enabled by the VIEW_RELOAD environment variable.
+//
+// Parameters:
+//   - fsys: The file system holding the templates, such as an embed.FS or os.DirFS("views").
+//   - options: Optional Options.
+//
+// Returns:
+//   - *Engine: The engine.
+//   - error: An error if a template could not be read or parsed.
+//
+// Example usage:
+//
+//	//go:embed views
+//	var views embed.FS
+//
+//	// views/layouts/base.html:  <html><body>{{block "content" .}}{{end}}</body></html>
+//	// views/users/list.html:    {{template "layouts/base" .}}{{define "content"}}<h1>{{t "users.title"}}</h1>{{end}}
+//	sub, _ := fs.Sub(views, "views")
+//	engine, err := view.New(sub)
+//	api.Views(engine)
+//
+//	func (s *Service) Users(ctx *goservectx.Request[*Principal]) {
+//		ctx.HTML(http.StatusOK, "users/list", s.users())
+//	}
+func New(fsys fs.FS, options ...Options) (*Engine, error) {
+	engine := &Engine{fsys: fsys}
+	if len(options) > 0 {
+		engine.options = options[0]
+	}
+	engine.options.Reload = engine.options.Reload || env.GetBoolEnvOrDefault("VIEW_RELOAD", false)
+
+	if engine.options.Extension == "" {
+		engine.options.Extension = ".html"
+	}
+	if engine.options.Layouts == "" {
+		engine.options.Layouts = "layouts"
+	}
+	if engine.options.Partials == "" {
+		engine.options.Partials = "partials"
+	}
+
+	if err := engine.Load(); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// Load parses the templates again, such as after they changed.
+func (e *Engine) Load() error {
+	shared := template.New("").Funcs((&binding{}).funcs()).Funcs(e.options.Funcs)
+	var pages []string
+
+	err := fs.WalkDir(e.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != e.options.Extension {
+			return err
+		}
+
+		if e.isShared(name) {
+			return e.parse(shared, name)
+		}
+		pages = append(pages, name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	parsed := make(map[string]*page, len(pages))
+	for _, name := range pages {
+		// The shared set is never executed, so it can always be cloned.
+		set, err := shared.Clone()
+		if err != nil {
+			return err
+		}
+
+		if err := e.parse(set, name); err != nil {
+			return err
+		}
+		parsed[e.nameOf(name)] = &page{set: set}
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.shared = &page{set: shared}
+	e.pages = parsed
+	return nil
+}
+
+func (e *Engine) isShared(name string) bool {
+	dir := strings.SplitN(name, "/", 2)[0]
+	return dir == e.options.Layouts || dir == e.options.Partials
+}
+
+func (e *Engine) nameOf(file string) string {
+	return strings.TrimSuffix(file, e.options.Extension)
+}
+
+func (e *Engine) parse(set *template.Template, file string) error {
+	content, err := fs.ReadFile(e.fsys, file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := set.New(e.nameOf(file)).Parse(string(content)); err != nil {
+		return fmt.Errorf("view: failed to parse %s: %w", file, err)
+	}
+	return nil
+}
+
+// Render executes the page, or the layout or partial, with the given name into w.
+//
+// Parameters:
+//   - w: The writer of the rendered HTML.
+//   - name: The template name, its path without the extension, such as users/list.
+//   - data: The data of the template.
+//   - request: The values of the request exposed to the template funcs.
+//
+// Returns:
+//   - error: An error if the template does not exist or failed to execute.
+func (e *Engine) Render(w io.Writer, name string, data any, request Request) error {
+	if e.options.Reload {
+		if err := e.Load(); err != nil {
+			return err
+		}
+	}
+
+	e.lock.RLock()
+	p, ok := e.pages[name]
+	if !ok {
+		p = e.shared
+	}
+	e.lock.RUnlock()
+
+	if p.set.Lookup(name) == nil {
+		return fmt.Errorf("view: template %q not found", name)
+	}
+
+	b, err := p.acquire()
+	if err != nil {
+		return err
+	}
+	defer p.release(b)
+
+	b.request = request
+	return b.set.ExecuteTemplate(w, name, data)
+}
+
+// acquire returns a clone of the page set from the pool, or a new one.
+func (p *page) acquire() (*binding, error) {
+	if b, ok := p.pool.Get().(*binding); ok {
+		return b, nil
+	}
+
+	set, err := p.set.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &binding{}
+	b.set = set.Funcs(b.funcs())
+	return b, nil
+}
+
+func (p *page) release(b *binding) {
+	b.request = Request{}
+	p.pool.Put(b)
+}
+
+// funcs returns the request funcs, which read the request the binding is rendering.
+func (b *binding) funcs() template.FuncMap {
+	return template.FuncMap{
+		"principal": func() any {
+			return b.request.Principal
+		},
+		"csrfToken": func() string {
+			return b.request.CsrfToken
+		},
+		"csrfField": func() template.HTML {
+			field := b.request.CsrfField
+			if field == "" {
+				field = DefaultCsrfField
+			}
+
+			return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+				template.HTMLEscapeString(field),
+				template.HTMLEscapeString(b.request.CsrfToken),
+			))
+		},
+		"locale": func() string {
+			return b.request.Locale
+		},
+		"t": func(key string, args ...string) string {
+			if b.request.Translate == nil {
+				return key
+			}
+			return b.request.Translate(key, args...)
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var funcs = template.FuncMap{"upper": strings.ToUpper}
+
+func TestEngine_Render(t *testing.T) {
+	engine, err := New(os.DirFS("testdata"), Options{Funcs: funcs})
+	require.NoError(t, err)
+
+	render := func(name string, data any, request Request) string {
+		var buffer bytes.Buffer
+		require.NoError(t, engine.Render(&buffer, name, data, request))
+		return strings.TrimSpace(buffer.String())
+	}
+
+	t.Run("should render the pages in their layout with the request funcs", func(t *testing.T) {
+		html := render("users/list", []string{"ana", "<bob>"}, Request{
+			Principal: "maria",
+			CsrfToken: `to"ken`,
+			Locale:    "pt-BR",
+			Translate: func(key string, args ...string) string { return "Usuários" },
+		})
+
+		require.Equal(t, `<html lang="pt-BR"><body><nav>maria</nav><h1>Usuários</h1>`+
+			`<form><input type="hidden" name="csrf_token" value="to&#34;ken"></form>`+
+			`<p>ANA</p><p>&lt;BOB&gt;</p></body></html>`, html)
+	})
+
+	t.Run("should keep the blocks of each page apart", func(t *testing.T) {
+		html := render("home", "welcome", Request{CsrfField: "_csrf"})
+
+		require.Equal(t, `<html lang=""><body><nav>guest</nav><p>welcome</p></body></html>`, html)
+	})
+
+	t.Run("should render partials by name", func(t *testing.T) {
+		require.Equal(t, `<nav>guest</nav>`, render("partials/nav", nil, Request{}))
+	})
+
+	t.Run("should bind each render to its own request", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				principal := fmt.Sprintf("user-%d", i)
+
+				var buffer bytes.Buffer
+				assert.NoError(t, engine.Render(&buffer, "partials/nav", nil, Request{Principal: principal}))
+				assert.Equal(t, "<nav>"+principal+"</nav>", strings.TrimSpace(buffer.String()))
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("should answer an error for unknown templates", func(t *testing.T) {
+		err := engine.Render(&bytes.Buffer{}, "missing", nil, Request{})
+		require.ErrorContains(t, err, `"missing" not found`)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should load embedded file systems", func(t *testing.T) {
+		engine, err := New(fstest.MapFS{
+			"pages/index.tmpl": {Data: []byte(`<b>{{.}}</b>`)},
+			"README.md":        {Data: []byte(`ignored {{`)},
+		}, Options{Extension: ".tmpl"})
+		require.NoError(t, err)
+
+		var buffer bytes.Buffer
+		require.NoError(t, engine.Render(&buffer, "pages/index", "hi", Request{}))
+		require.Equal(t, `<b>hi</b>`, buffer.String())
+	})
+
+	t.Run("should answer the template parse errors", func(t *testing.T) {
+		_, err := New(fstest.MapFS{"broken.html": {Data: []byte(`{{if}}`)}})
+		require.ErrorContains(t, err, "broken.html")
+	})
+
+	t.Run("should reload the templates on every render", func(t *testing.T) {
+		dir := t.TempDir()
+		page := filepath.Join(dir, "page.html")
+		require.NoError(t, os.WriteFile(page, []byte(`v1`), 0o600))
+
+		engine, err := New(os.DirFS(dir), Options{Reload: true})
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(page, []byte(`v2`), 0o600))
+
+		var buffer bytes.Buffer
+		require.NoError(t, engine.Render(&buffer, "page", nil, Request{}))
+		require.Equal(t, "v2", buffer.String())
+	})
+}
+
+func BenchmarkEngine_Render(b *testing.B) {
+	engine, err := New(os.DirFS("testdata"), Options{Funcs: funcs})
+	require.NoError(b, err)
+
+	request := Request{Principal: "maria", CsrfToken: "token", Locale: "en"}
+	users := []string{"ana", "bob"}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var buffer bytes.Buffer
+		for pb.Next() {
+			buffer.Reset()
+			if err := engine.Render(&buffer, "users/list", users, request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}